@@ -0,0 +1,20 @@
+// Package autoload loads the .env file from the current directory as a side
+// effect of being imported:
+//
+//	import _ "github.com/pechorka/dotenv/autoload"
+//
+// A missing file is ignored. Other errors are reported through the standard
+// log package and do not stop the program.
+package autoload
+
+import (
+	"log"
+
+	"github.com/pechorka/dotenv"
+)
+
+func init() {
+	if err := dotenv.Load(); err != nil {
+		log.Printf("dotenv/autoload: %v", err)
+	}
+}
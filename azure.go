@@ -0,0 +1,69 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// AzureSecret describes a secret listed by an AzureKeyVaultClient.
+type AzureSecret struct {
+	Name    string
+	Tags    map[string]string
+	Enabled bool
+}
+
+// AzureKeyVaultClient is the part of the Azure Key Vault secrets API used by
+// AzureKeyVaultProvider. An empty version means the latest one. The package
+// does not depend on the Azure SDK; adapt its azsecrets client with a few
+// lines as shown for SSMClient.
+type AzureKeyVaultClient interface {
+	GetSecret(ctx context.Context, name, version string) (string, error)
+	ListSecrets(ctx context.Context) ([]AzureSecret, error)
+}
+
+// AzureKeyVaultProvider reads secrets from an Azure Key Vault, either the
+// ones named in Secrets or, when Tags is set, every enabled secret carrying
+// all of those tags. Secret names become keys through NormalizeUpperSnake,
+// so db-password is exported as DB_PASSWORD.
+type AzureKeyVaultProvider struct {
+	Client AzureKeyVaultClient
+	// Vault names the vault in reports, e.g. its URL.
+	Vault   string
+	Secrets []string
+	Tags    map[string]string
+}
+
+// Fetch reads the selected secrets in their latest version.
+func (p *AzureKeyVaultProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	if p.Client == nil {
+		return nil, errors.New("azure key vault: client is required")
+	}
+	names := p.Secrets
+	if len(p.Tags) > 0 {
+		secrets, err := p.Client.ListSecrets(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("azure key vault %s: list secrets: %w", p.Vault, err)
+		}
+		for _, s := range secrets {
+			if s.Enabled && hasLabels(s.Tags, p.Tags) {
+				names = append(names[:len(names):len(names)], s.Name)
+			}
+		}
+	}
+
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		v, err := p.Client.GetSecret(ctx, name, "")
+		if err != nil {
+			return nil, fmt.Errorf("azure key vault %s: %s: %w", p.Vault, name, err)
+		}
+		values[NormalizeUpperSnake(name)] = v
+	}
+	return values, nil
+}
+
+// String returns an azurekeyvault:// URL naming the vault.
+func (p *AzureKeyVaultProvider) String() string {
+	return "azurekeyvault://" + p.Vault
+}
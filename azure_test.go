@@ -0,0 +1,34 @@
+package dotenv
+
+import (
+	"context"
+	"testing"
+)
+
+type fakeKeyVault map[string]string
+
+func (f fakeKeyVault) GetSecret(_ context.Context, name, _ string) (string, error) {
+	return f[name], nil
+}
+
+func (f fakeKeyVault) ListSecrets(context.Context) ([]AzureSecret, error) {
+	return []AzureSecret{
+		{Name: "db-password", Tags: map[string]string{"env": "prod"}, Enabled: true},
+		{Name: "old-password", Tags: map[string]string{"env": "prod"}},
+		{Name: "dev-token", Tags: map[string]string{"env": "dev"}, Enabled: true},
+	}, nil
+}
+
+func Test_azureKeyVaultProvider(t *testing.T) {
+	client := fakeKeyVault{"db-password": "s3cret", "api-token": "t"}
+
+	p := &AzureKeyVaultProvider{Client: client, Vault: "https://app.vault.azure.net", Tags: map[string]string{"env": "prod"}}
+	env, err := p.Fetch(context.Background())
+	assertNoError(t, err)
+	assertEqual(t, len(env), 1)
+	assertEqual(t, env["DB_PASSWORD"], "s3cret")
+
+	env, err = (&AzureKeyVaultProvider{Client: client, Secrets: []string{"api-token"}}).Fetch(context.Background())
+	assertNoError(t, err)
+	assertEqual(t, env["API_TOKEN"], "t")
+}
@@ -0,0 +1,43 @@
+package dotenv
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// WithBase64Values decodes values written as "base64:<data>" before they are
+// exported, so that binary or multiline data fits on a single line:
+//
+//	CERT=base64:LS0tLS1CRUdJTi...
+//
+// Both padded and unpadded standard encoding are accepted. Invalid data is an
+// error.
+func WithBase64Values() Option {
+	return func(o *Options) {
+		o.Base64Values = true
+	}
+}
+
+func decodeBase64Values(opts Options, env entries) error {
+	if !opts.Base64Values {
+		return nil
+	}
+	for key, e := range env {
+		data, ok := strings.CutPrefix(e.value, "base64:")
+		if !ok || e.unset {
+			continue
+		}
+		enc := base64.StdEncoding
+		if !strings.HasSuffix(data, "=") {
+			enc = base64.RawStdEncoding
+		}
+		decoded, err := enc.DecodeString(data)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %s: invalid base64 value: %w", e.file, e.line, key, err)
+		}
+		e.value = string(decoded)
+		env[key] = e
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package dotenv
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_base64Values(t *testing.T) {
+	const content = "CERT=base64:LS0tLS1CRUdJTgpsaW5lCg==\nRAW=base64:aGk\nPLAIN=hello\n"
+
+	env, err := ParseString(content, WithBase64Values())
+	assertNoError(t, err)
+	assertEqual(t, env["CERT"], "-----BEGIN\nline\n")
+	assertEqual(t, env["RAW"], "hi")
+	assertEqual(t, env["PLAIN"], "hello")
+
+	env, err = ParseString(content)
+	assertNoError(t, err)
+	assertEqual(t, env["RAW"], "base64:aGk")
+
+	_, err = ParseString("BAD=base64:!!!\n", WithBase64Values())
+	if err == nil || !strings.Contains(err.Error(), "<string>:1: BAD: invalid base64 value") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
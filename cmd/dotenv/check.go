@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pechorka/dotenv"
+	"github.com/pechorka/dotenv/dotenvlint"
+)
+
+// checkCmd implements "dotenv check [-fix] [file...]": it applies the rules
+// of package dotenvlint to each file (.env by default) and prints every
+// finding as file:line:col: message (rule). It fails when there are
+// findings, so it can gate CI. With -fix the suggested fixes are written
+// back first and only what is left is reported.
+func checkCmd(args []string, stdout, stderr io.Writer) error {
+	var fix bool
+	fs := newFlagSet("check", "[file...]", stderr)
+	fs.BoolVar(&fix, "fix", false, "apply suggested fixes in place")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{".env"}
+	}
+
+	failed := false
+	for _, file := range files {
+		findings, err := checkFile(file, fix)
+		if err != nil {
+			return err
+		}
+		for _, f := range findings {
+			fmt.Fprintf(stdout, "%s:%d:%d: %s (%s)\n", file, f.Line, f.Col, f.Message, f.Rule)
+		}
+		failed = failed || len(findings) > 0
+	}
+	if failed {
+		return &exitError{code: 1}
+	}
+	return nil
+}
+
+// checkFile returns the findings for file, fixing it first if fix is set.
+// A file that can't be parsed at all yields a single syntax finding.
+func checkFile(file string, fix bool) ([]dotenvlint.Finding, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := dotenv.ParseDocument(bytes.NewReader(data))
+	var perr *dotenv.ParseError
+	if errors.As(err, &perr) {
+		return []dotenvlint.Finding{{
+			Line: perr.Line, Col: perr.Col, Rule: dotenvlint.RuleSyntax,
+			Severity: dotenvlint.SeverityError, Message: perr.Reason,
+		}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	findings := dotenvlint.Check(doc)
+	if !fix {
+		return findings, nil
+	}
+	// Fixes of one statement are applied one per pass.
+	orig := doc.Bytes()
+	for {
+		data := dotenvlint.ApplyFixes(doc, findings)
+		if bytes.Equal(data, doc.Bytes()) {
+			break
+		}
+		if doc, err = dotenv.ParseDocument(bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("%s: fixing: %w", file, err)
+		}
+		findings = dotenvlint.Check(doc)
+	}
+	if !bytes.Equal(doc.Bytes(), orig) {
+		if err := doc.Save(file); err != nil {
+			return nil, err
+		}
+	}
+	return findings, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_check(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".env": "A=1\nB='two words'\n",
+		"bad.env": `# comment
+A=1
+BROKEN
+B = 2
+C= x
+EMPTY= # nothing
+lower=1
+export export_me=1
+SPACES=two words
+A=3
+`,
+	})
+
+	code, stdout, _ := runCLI(t, dir, "check")
+	assertEqual(t, code, 0)
+	assertEqual(t, stdout, "")
+
+	code, stdout, _ = runCLI(t, dir, "check", ".env", "bad.env")
+	assertEqual(t, code, 1)
+	assertEqual(t, stdout, `bad.env:3:1: missing '=' after key (syntax)
+bad.env:4:2: space before '=' (spacing)
+bad.env:4:4: space after '=' (spacing)
+bad.env:5:3: space after '=' (spacing)
+bad.env:7:1: key lower is not upper case (uppercase)
+bad.env:8:8: key export_me is not upper case (uppercase)
+bad.env:9:8: value of SPACES contains spaces but is not quoted (unquoted)
+bad.env:10:1: duplicate key A, first defined on line 2 (duplicate)
+`)
+
+	code, _, stderr := runCLI(t, dir, "check", "missing.env")
+	assertEqual(t, code, 1)
+	assertContains(t, stderr, "missing.env")
+}
+
+func Test_checkFix(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".env": "A=1\nBROKEN\nB = two words # note\nlower=1\nA=2\n",
+	})
+
+	code, stdout, _ := runCLI(t, dir, "check", "-fix")
+	assertEqual(t, code, 1)
+	assertEqual(t, stdout, ".env:1:1: missing '=' after key (syntax)\n")
+	data, err := os.ReadFile(filepath.Join(dir, ".env"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, string(data), "BROKEN\nB=\"two words\" # note\nLOWER=1\nA=2\n")
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pechorka/dotenv"
+)
+
+// keygenCmd implements "dotenv keygen [-f file]": it generates a key pair
+// and prints the private key as the variable decryption reads it from.
+// With -f the public key is added to the file, which must not have one yet;
+// otherwise it is printed too.
+func keygenCmd(args []string, stdout, stderr io.Writer) error {
+	var file string
+	fs := newFlagSet("keygen", "", stderr)
+	fs.StringVar(&file, "f", "", "add the public key to `file`")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	pub, priv, err := dotenv.GenerateKeyPair()
+	if err != nil {
+		return err
+	}
+	if file == "" {
+		fmt.Fprintf(stdout, "%s=%s\n%s=%s\n", dotenv.PublicKeyName, pub, dotenv.PrivateKeyName(".env"), priv)
+		return nil
+	}
+
+	doc, err := openDocument(file)
+	if err != nil {
+		return err
+	}
+	if _, ok := doc.Get(dotenv.PublicKeyName); ok {
+		return fmt.Errorf("%s already has a %s", file, dotenv.PublicKeyName)
+	}
+	if err := doc.Set(dotenv.PublicKeyName, pub); err != nil {
+		return err
+	}
+	if err := doc.Save(file); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%s=%s\n", dotenv.PrivateKeyName(file), priv)
+	return nil
+}
+
+// encryptCmd implements "dotenv encrypt [-f file] [KEY...]": it encrypts the
+// values of the keys, or of every key, in place to the public key in the
+// file. Values that are already encrypted are left alone.
+func encryptCmd(args []string, stdout, stderr io.Writer) error {
+	var file string
+	fs := newFlagSet("encrypt", "[KEY...]", stderr)
+	fs.StringVar(&file, "f", ".env", "edit `file`")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	doc, err := openDocument(file)
+	if err != nil {
+		return err
+	}
+	pub, ok := doc.Get(dotenv.PublicKeyName)
+	if !ok {
+		return fmt.Errorf("%s has no %s, run dotenv keygen -f %s", file, dotenv.PublicKeyName, file)
+	}
+	keys, err := selectKeys(doc, fs.Args())
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		value, _ := doc.Get(key)
+		if strings.HasPrefix(value, dotenv.EncryptedPrefix) {
+			continue
+		}
+		if value, err = dotenv.Encrypt(value, pub); err != nil {
+			return fmt.Errorf("encrypt %s: %w", key, err)
+		}
+		if err := doc.Set(key, value); err != nil {
+			return err
+		}
+	}
+	return doc.Save(file)
+}
+
+// decryptCmd implements "dotenv decrypt [-f file] [-stdout] [KEY...]": it
+// decrypts the values of the keys, or of every key, in place. The private
+// key is read from the same variables WithDecryption uses, unless -key is
+// given. With -stdout the file is left untouched and the decrypted content
+// is printed instead.
+func decryptCmd(args []string, stdout, stderr io.Writer) error {
+	var (
+		file     string
+		key      string
+		toStdout bool
+	)
+	fs := newFlagSet("decrypt", "[KEY...]", stderr)
+	fs.StringVar(&file, "f", ".env", "edit `file`")
+	fs.StringVar(&key, "key", "", "hex private `key`")
+	fs.BoolVar(&toStdout, "stdout", false, "print the decrypted file instead of writing it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	privs := privateKeys(file, key)
+	if len(privs) == 0 {
+		return fmt.Errorf("no private key, set %s or use -key", dotenv.PrivateKeyName(file))
+	}
+	doc, err := openDocument(file)
+	if err != nil {
+		return err
+	}
+	keys, err := selectKeys(doc, fs.Args())
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		value, _ := doc.Get(k)
+		if !strings.HasPrefix(value, dotenv.EncryptedPrefix) {
+			continue
+		}
+		plain, err := decrypt(value, privs)
+		if err != nil {
+			return fmt.Errorf("decrypt %s: %w", k, err)
+		}
+		if err := doc.Set(k, plain); err != nil {
+			return err
+		}
+	}
+	if toStdout {
+		_, err := doc.WriteTo(stdout)
+		return err
+	}
+	return doc.Save(file)
+}
+
+// selectKeys returns keys, checking that doc defines them, or every key of
+// doc but the public key when keys is empty.
+func selectKeys(doc *dotenv.Document, keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return slices.DeleteFunc(doc.Keys(), func(k string) bool { return k == dotenv.PublicKeyName }), nil
+	}
+	for _, k := range keys {
+		if _, ok := doc.Get(k); !ok {
+			return nil, fmt.Errorf("%s is not defined", k)
+		}
+	}
+	return keys, nil
+}
+
+// privateKeys returns the private keys to try for file: key if given, or
+// the comma separated keys in the variable named by PrivateKeyName or in
+// DOTENV_PRIVATE_KEY.
+func privateKeys(file, key string) []string {
+	if key != "" {
+		return []string{key}
+	}
+	for _, name := range []string{dotenv.PrivateKeyName(file), "DOTENV_PRIVATE_KEY"} {
+		if keys := os.Getenv(name); keys != "" {
+			return strings.Split(keys, ",")
+		}
+	}
+	return nil
+}
+
+// decrypt decrypts value with the first of keys that fits.
+func decrypt(value string, keys []string) (string, error) {
+	var errs []error
+	for _, key := range keys {
+		plain, err := dotenv.Decrypt(value, key)
+		if err == nil {
+			return plain, nil
+		}
+		errs = append(errs, err)
+	}
+	return "", errors.Join(errs...)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pechorka/dotenv"
+)
+
+func Test_crypt(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".env.production": "# secrets\nAPI_KEY=s3cret # rotated monthly\nHOST=db\n",
+	})
+	read := func() string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, ".env.production"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	code, stdout, _ := runCLI(t, dir, "keygen", "-f", ".env.production")
+	assertEqual(t, code, 0)
+	name, priv, ok := strings.Cut(strings.TrimSpace(stdout), "=")
+	assertEqual(t, ok, true)
+	assertEqual(t, name, "DOTENV_PRIVATE_KEY_PRODUCTION")
+	assertContains(t, read(), dotenv.PublicKeyName+"=")
+
+	code, _, stderr := runCLI(t, dir, "keygen", "-f", ".env.production")
+	assertEqual(t, code, 1)
+	assertContains(t, stderr, "already has a DOTENV_PUBLIC_KEY")
+
+	code, _, _ = runCLI(t, dir, "encrypt", "-f", ".env.production", "API_KEY")
+	assertEqual(t, code, 0)
+	encrypted := read()
+	assertContains(t, encrypted, "API_KEY="+dotenv.EncryptedPrefix)
+	assertContains(t, encrypted, " # rotated monthly\nHOST=db\n")
+
+	t.Run("loads with -decrypt", func(t *testing.T) {
+		t.Setenv("DOTENV_PRIVATE_KEY_PRODUCTION", priv)
+		code, stdout, _ := runCLI(t, dir, "get", "-f", ".env.production", "-decrypt", "API_KEY")
+		assertEqual(t, code, 0)
+		assertEqual(t, stdout, "s3cret\n")
+	})
+
+	t.Run("decrypt", func(t *testing.T) {
+		code, _, stderr := runCLI(t, dir, "decrypt", "-f", ".env.production")
+		assertEqual(t, code, 1)
+		assertContains(t, stderr, "set DOTENV_PRIVATE_KEY_PRODUCTION")
+
+		t.Setenv("DOTENV_PRIVATE_KEY_PRODUCTION", priv)
+		code, stdout, _ := runCLI(t, dir, "decrypt", "-f", ".env.production", "-stdout")
+		assertEqual(t, code, 0)
+		assertContains(t, stdout, "API_KEY=s3cret # rotated monthly\n")
+		assertEqual(t, read(), encrypted)
+
+		code, _, _ = runCLI(t, dir, "decrypt", "-f", ".env.production")
+		assertEqual(t, code, 0)
+		assertContains(t, read(), "API_KEY=s3cret # rotated monthly\n")
+	})
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/pechorka/dotenv"
+)
+
+// diffCmd implements "dotenv diff [flags] OLD NEW": it prints the keys NEW
+// adds (+), removes (-) and changes (~) compared to OLD, sorted by key. OLD
+// and NEW are files or directories; with -profile a directory stands for
+// the layered files of that environment. Values are hidden unless -values
+// is given.
+func diffCmd(args []string, stdout, stderr io.Writer) error {
+	var (
+		lf       loadFlags
+		values   bool
+		exitCode bool
+	)
+	fs := newFlagSet("diff", "OLD NEW", stderr)
+	fs.StringVar(&lf.profile, "profile", "", "load the layered files of `profile`")
+	fs.BoolVar(&lf.expand, "expand", false, "expand $VAR references")
+	fs.BoolVar(&values, "values", false, "print values")
+	fs.BoolVar(&exitCode, "exit-code", false, "exit with 1 when there are differences")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	changes, err := dotenv.DiffFiles(fs.Arg(0), fs.Arg(1), lf.options(stderr)...)
+	if err != nil {
+		return err
+	}
+
+	type line struct {
+		op  byte
+		key string
+		val string
+	}
+	var lines []line
+	for _, c := range changes.Added {
+		lines = append(lines, line{'+', c.Key, "=" + c.New})
+	}
+	for _, c := range changes.Removed {
+		lines = append(lines, line{'-', c.Key, "=" + c.Old})
+	}
+	for _, c := range changes.Changed {
+		lines = append(lines, line{'~', c.Key, "=" + c.Old + " -> " + c.New})
+	}
+	slices.SortFunc(lines, func(a, b line) int { return cmp.Compare(a.key, b.key) })
+	for _, l := range lines {
+		if !values {
+			l.val = ""
+		}
+		fmt.Fprintf(stdout, "%c %s%s\n", l.op, l.key, l.val)
+	}
+
+	if exitCode && !changes.Empty() {
+		return &exitError{code: 1}
+	}
+	return nil
+}
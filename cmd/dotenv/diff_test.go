@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_diff(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".env":            "SAME=1\nHOST=localhost\nDEBUG=true\n",
+		".env.production": "SAME=1\nHOST=db.internal\nREPLICAS=3\n",
+	})
+
+	code, stdout, _ := runCLI(t, dir, "diff", ".env", ".env.production")
+	assertEqual(t, code, 0)
+	assertEqual(t, stdout, "- DEBUG\n~ HOST\n+ REPLICAS\n")
+
+	code, stdout, _ = runCLI(t, dir, "diff", "-values", "-exit-code", ".env", ".env.production")
+	assertEqual(t, code, 1)
+	assertEqual(t, stdout, "- DEBUG=true\n~ HOST=localhost -> db.internal\n+ REPLICAS=3\n")
+
+	code, stdout, _ = runCLI(t, dir, "diff", "-exit-code", ".env", ".env")
+	assertEqual(t, code, 0)
+	assertEqual(t, stdout, "")
+
+	t.Run("environments", func(t *testing.T) {
+		for _, name := range []string{"staging", "production"} {
+			if err := os.Mkdir(filepath.Join(dir, name), 0o700); err != nil {
+				t.Fatal(err)
+			}
+		}
+		files := map[string]string{
+			"staging/.env":               "HOST=a\n",
+			"staging/.env.prod":          "HOST=staging\n",
+			"production/.env":            "HOST=a\n",
+			"production/.env.prod":       "HOST=production\n",
+			"production/.env.prod.local": "EXTRA=1\n",
+		}
+		for name, data := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		code, stdout, _ := runCLI(t, dir, "diff", "-profile", "prod", "-values", "staging", "production")
+		assertEqual(t, code, 0)
+		assertEqual(t, stdout, "+ EXTRA=1\n~ HOST=staging -> production\n")
+	})
+}
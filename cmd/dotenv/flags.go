@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/pechorka/dotenv"
+)
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+// loadFlags are the flags of commands that read .env files.
+type loadFlags struct {
+	files      stringList
+	profile    string
+	expand     bool
+	strict     bool
+	noOverride bool
+	decrypt    bool
+}
+
+func (f *loadFlags) register(fs *flag.FlagSet) {
+	fs.Var(&f.files, "f", "read `file` (repeatable; default .env)")
+	fs.StringVar(&f.profile, "profile", "", "load the layered files of `profile`")
+	fs.BoolVar(&f.expand, "expand", false, "expand $VAR references")
+	fs.BoolVar(&f.strict, "strict", false, "fail on malformed lines")
+	fs.BoolVar(&f.noOverride, "no-override", false, "keep variables that are already set")
+	fs.BoolVar(&f.decrypt, "decrypt", false, "decrypt encrypted values with DOTENV_PRIVATE_KEY")
+}
+
+// options returns the library options selected by the flags. Warnings such
+// as missing files are logged to stderr.
+func (f *loadFlags) options(stderr io.Writer) []dotenv.Option {
+	logger := slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	opts := []dotenv.Option{dotenv.WithSlog(logger), dotenv.WithExpand(f.expand)}
+	if len(f.files) > 0 {
+		opts = append(opts, dotenv.WithPaths(f.files...))
+	}
+	if f.profile != "" {
+		opts = append(opts, dotenv.WithProfile(f.profile))
+	}
+	if f.strict {
+		opts = append(opts, dotenv.WithStrict())
+	}
+	if f.noOverride {
+		opts = append(opts, dotenv.WithNoOverride())
+	}
+	if f.decrypt {
+		opts = append(opts, dotenv.WithDecryption())
+	}
+	return opts
+}
+
+// newFlagSet returns a flag set for the command name that reports errors
+// to stderr.
+func newFlagSet(name, args string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("dotenv "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		io.WriteString(stderr, "Usage: dotenv "+name+" [flags] "+args+"\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	return fs
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pechorka/dotenv"
+)
+
+// fmtCmd implements "dotenv fmt [flags] [file...]": it prints each file
+// (.env by default) in canonical style: trimmed whitespace, normalized
+// quotes and aligned comments, with -sort also sorted by key. -w rewrites
+// the files instead and -l lists the files whose style differs, failing if
+// there are any, so CI can enforce the style.
+func fmtCmd(args []string, stdout, stderr io.Writer) error {
+	var write, list, sortKeys bool
+	fs := newFlagSet("fmt", "[file...]", stderr)
+	fs.BoolVar(&write, "w", false, "write the result to the files")
+	fs.BoolVar(&list, "l", false, "list files whose formatting differs")
+	fs.BoolVar(&sortKeys, "sort", false, "sort entries by key")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{".env"}
+	}
+	opts := dotenv.FormatOptions{
+		SortKeys:        sortKeys,
+		NormalizeQuotes: true,
+		TrimWhitespace:  true,
+		AlignComments:   true,
+	}
+
+	unformatted := false
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		doc, err := dotenv.ParseDocument(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		formatted := dotenv.FormatDocument(doc, opts)
+		changed := !bytes.Equal(formatted, data)
+		switch {
+		case list:
+			if changed {
+				fmt.Fprintln(stdout, file)
+				unformatted = true
+			}
+		case write:
+			if changed {
+				if err := writeFormatted(file, formatted); err != nil {
+					return err
+				}
+			}
+		default:
+			stdout.Write(formatted)
+		}
+	}
+	if unformatted {
+		return &exitError{code: 1}
+	}
+	return nil
+}
+
+// writeFormatted atomically replaces file with data.
+func writeFormatted(file string, data []byte) error {
+	doc, err := dotenv.ParseDocument(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	return doc.Save(file)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_fmt(t *testing.T) {
+	const messy = "  B = 'two'   # second\nA=\"one\" # first\n\n\n"
+	dir := writeFiles(t, map[string]string{
+		".env":     messy,
+		"tidy.env": "A=one\n",
+	})
+
+	code, stdout, _ := runCLI(t, dir, "fmt")
+	assertEqual(t, code, 0)
+	assertEqual(t, stdout, "B=two # second\nA=one # first\n")
+
+	code, stdout, _ = runCLI(t, dir, "fmt", "-sort")
+	assertEqual(t, code, 0)
+	assertEqual(t, stdout, "A=one # first\nB=two # second\n")
+
+	code, stdout, _ = runCLI(t, dir, "fmt", "-l", ".env", "tidy.env")
+	assertEqual(t, code, 1)
+	assertEqual(t, stdout, ".env\n")
+
+	code, _, _ = runCLI(t, dir, "fmt", "-w", "-sort")
+	assertEqual(t, code, 0)
+	data, err := os.ReadFile(filepath.Join(dir, ".env"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, string(data), "A=one # first\nB=two # second\n")
+}
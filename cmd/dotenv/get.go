@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/pechorka/dotenv"
+)
+
+// getCmd implements "dotenv get [flags] KEY": it prints the value KEY ends
+// up with after merging the files and fails when no file defines it.
+func getCmd(args []string, stdout, stderr io.Writer) error {
+	var lf loadFlags
+	fs := newFlagSet("get", "KEY", stderr)
+	lf.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	env, err := dotenv.Parse(lf.options(stderr)...)
+	if err != nil {
+		return err
+	}
+	value, ok := env[fs.Arg(0)]
+	if !ok {
+		return fmt.Errorf("%s is not defined", fs.Arg(0))
+	}
+	fmt.Fprintln(stdout, value)
+	return nil
+}
+
+// listCmd implements "dotenv list [flags]": it prints the merged variables
+// as sorted KEY=value lines. -mask hides every value and -mask-secrets only
+// those that look like secrets.
+func listCmd(args []string, stdout, stderr io.Writer) error {
+	var (
+		lf          loadFlags
+		mask        bool
+		maskSecrets bool
+	)
+	fs := newFlagSet("list", "", stderr)
+	lf.register(fs)
+	fs.BoolVar(&mask, "mask", false, "hide all values")
+	fs.BoolVar(&maskSecrets, "mask-secrets", false, "hide values that look like secrets")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	env, err := dotenv.Parse(lf.options(stderr)...)
+	if err != nil {
+		return err
+	}
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		value := env[key]
+		if mask || maskSecrets && dotenv.LikelySecret(key, value) {
+			value = dotenv.Masked
+		}
+		fmt.Fprintf(stdout, "%s=%s\n", key, value)
+	}
+	return nil
+}
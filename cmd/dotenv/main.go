@@ -0,0 +1,105 @@
+// Command dotenv works with .env files from the shell.
+//
+// Usage:
+//
+//	dotenv <command> [flags] [arguments]
+//
+// The commands are:
+//
+//	run     run a command with the variables from .env files
+//	get     print the value of a variable
+//	list    print all variables
+//	set     set variables in a .env file
+//	unset   remove variables from a .env file
+//	check   report malformed lines and style problems in .env files
+//	fmt     format .env files
+//	diff    compare the variables of two files or environments
+//	keygen  generate a key pair for encrypted values
+//	encrypt encrypt values in a .env file
+//	decrypt decrypt values in a .env file
+//
+// Run "dotenv <command> -h" for the flags of a command. Commands that read
+// .env files accept -f to name them (.env by default, repeatable, later
+// files override earlier ones), -profile, -expand, -strict, -no-override
+// and -decrypt, matching the options of the library.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// command is a subcommand of the CLI.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) error
+}
+
+var commands = []command{
+	{"run", "run a command with the variables from .env files", runCmd},
+	{"get", "print the value of a variable", getCmd},
+	{"list", "print all variables", listCmd},
+	{"set", "set variables in a .env file", setCmd},
+	{"unset", "remove variables from a .env file", unsetCmd},
+	{"check", "report malformed lines and style problems in .env files", checkCmd},
+	{"fmt", "format .env files", fmtCmd},
+	{"diff", "compare the variables of two files or environments", diffCmd},
+	{"keygen", "generate a key pair for encrypted values", keygenCmd},
+	{"encrypt", "encrypt values in a .env file", encryptCmd},
+	{"decrypt", "decrypt values in a .env file", decryptCmd},
+}
+
+// exitError makes the CLI exit with code without printing anything.
+type exitError struct {
+	code int
+}
+
+func (e *exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+func main() {
+	os.Exit(cli(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// cli runs the command named by args[0] and returns the exit code.
+func cli(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		usage(stderr)
+		return 2
+	}
+	for _, c := range commands {
+		if c.name != args[0] {
+			continue
+		}
+		err := c.run(args[1:], stdout, stderr)
+		var exit *exitError
+		switch {
+		case err == nil:
+			return 0
+		case errors.As(err, &exit):
+			return exit.code
+		case errors.Is(err, flag.ErrHelp):
+			return 2
+		}
+		fmt.Fprintf(stderr, "dotenv %s: %v\n", c.name, err)
+		return 1
+	}
+	fmt.Fprintf(stderr, "dotenv: unknown command %q\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	var b strings.Builder
+	b.WriteString("Usage: dotenv <command> [flags] [arguments]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(&b, "  %-8s %s\n", c.name, c.summary)
+	}
+	io.WriteString(w, b.String())
+}
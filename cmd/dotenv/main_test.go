@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMain lets the test binary stand in for the commands run by the CLI:
+// with DOTENV_TEST_HELPER set it prints the variables named by its
+// arguments and exits with DOTENV_TEST_EXIT.
+func TestMain(m *testing.M) {
+	if os.Getenv("DOTENV_TEST_HELPER") == "1" {
+		for _, key := range os.Args[1:] {
+			fmt.Printf("%s=%s\n", key, os.Getenv(key))
+		}
+		code := 0
+		fmt.Sscan(os.Getenv("DOTENV_TEST_EXIT"), &code)
+		os.Exit(code)
+	}
+	os.Exit(m.Run())
+}
+
+// runCLI runs the CLI in dir and returns its exit code and output.
+func runCLI(t *testing.T, dir string, args ...string) (int, string, string) {
+	t.Helper()
+	t.Chdir(dir)
+	var stdout, stderr bytes.Buffer
+	code := cli(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// writeFiles creates files in a temporary directory and returns it.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func assertEqual[T comparable](t *testing.T, got, want T) {
+	t.Helper()
+	if got != want {
+		t.Fatalf("got=%v, want=%v", got, want)
+	}
+}
+
+func assertContains(t *testing.T, s, substr string) {
+	t.Helper()
+	if !strings.Contains(s, substr) {
+		t.Fatalf("%q does not contain %q", s, substr)
+	}
+}
+
+func Test_cli(t *testing.T) {
+	code, _, stderr := runCLI(t, t.TempDir(), "nope")
+	assertEqual(t, code, 2)
+	assertContains(t, stderr, `unknown command "nope"`)
+	assertContains(t, stderr, "run ")
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pechorka/dotenv"
+)
+
+// runCmd implements "dotenv run [flags] -- command [args...]". The command
+// inherits the environment with the parsed values merged over it; the
+// shell running dotenv is left untouched. Interrupts are forwarded to the
+// command and its exit code becomes the exit code of dotenv. With -watch
+// the command is restarted whenever the variables change.
+func runCmd(args []string, stdout, stderr io.Writer) error {
+	var (
+		lf       loadFlags
+		watch    bool
+		interval time.Duration
+	)
+	fs := newFlagSet("run", "-- command [args...]", stderr)
+	lf.register(fs)
+	fs.BoolVar(&watch, "watch", false, "restart the command when the variables change")
+	fs.DurationVar(&interval, "interval", dotenv.DefaultReloadInterval, "how often -watch checks the files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	opts := lf.options(stderr)
+	newCmd := func() (*exec.Cmd, error) {
+		cmd := exec.Command(fs.Arg(0), fs.Args()[1:]...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, stdout, stderr
+		return cmd, dotenv.ApplyToCmd(cmd, opts...)
+	}
+	if watch {
+		return watchChild(newCmd, append(opts, dotenv.WithReloadInterval(interval)), stderr)
+	}
+	cmd, err := newCmd()
+	if err != nil {
+		return err
+	}
+	return runChild(cmd)
+}
+
+// runChild runs cmd, forwarding interrupts to it, and converts its exit
+// status into an *exitError.
+func runChild(cmd *exec.Cmd) error {
+	signals := notifyInterrupts()
+	defer signal.Stop(signals)
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	for {
+		select {
+		case sig := <-signals:
+			cmd.Process.Signal(sig)
+		case err := <-done:
+			return exitStatus(err)
+		}
+	}
+}
+
+// notifyInterrupts returns a channel receiving the signals that stop dotenv.
+func notifyInterrupts() chan os.Signal {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	return signals
+}
+
+// exitStatus converts the result of cmd.Wait into an *exitError carrying
+// the exit code of the command. A command killed by a signal exits with
+// 128 plus the signal number, as in shells.
+func exitStatus(err error) error {
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		if status, ok := exit.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return &exitError{code: 128 + int(status.Signal())}
+		}
+		return &exitError{code: max(exit.ExitCode(), 1)}
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+)
+
+func Test_run(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".env":     "RUN_A=from-env\nRUN_B=b\n",
+		"prod.env": "RUN_A=from-prod\n",
+	})
+	t.Setenv("DOTENV_TEST_HELPER", "1")
+	t.Setenv("RUN_PRESET", "kept")
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("passes the merged environment", func(t *testing.T) {
+		code, stdout, _ := runCLI(t, dir, "run", "-f", ".env", "-f", "prod.env", "--", self, "RUN_A", "RUN_B", "RUN_PRESET")
+		assertEqual(t, code, 0)
+		assertEqual(t, stdout, "RUN_A=from-prod\nRUN_B=b\nRUN_PRESET=kept\n")
+		_, set := os.LookupEnv("RUN_A")
+		assertEqual(t, set, false)
+	})
+
+	t.Run("exits with the command's code", func(t *testing.T) {
+		t.Setenv("DOTENV_TEST_EXIT", "3")
+		code, _, _ := runCLI(t, dir, "run", "--", self)
+		assertEqual(t, code, 3)
+	})
+
+	t.Run("exits with 128 plus the signal of a killed command", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("no signals on windows")
+		}
+		code, _, _ := runCLI(t, dir, "run", "--", "sh", "-c", "kill -TERM $$")
+		assertEqual(t, code, 128+int(syscall.SIGTERM))
+		code, _, _ = runCLI(t, dir, "run", "--", "sh", "-c", "kill -KILL $$")
+		assertEqual(t, code, 128+int(syscall.SIGKILL))
+	})
+
+	t.Run("requires a command", func(t *testing.T) {
+		code, _, stderr := runCLI(t, dir, "run")
+		assertEqual(t, code, 2)
+		assertContains(t, stderr, "Usage: dotenv run")
+	})
+
+	t.Run("reports parse errors", func(t *testing.T) {
+		bad := writeFiles(t, map[string]string{".env": "BROKEN\n"})
+		code, _, stderr := runCLI(t, bad, "run", "-strict", "--", self)
+		assertEqual(t, code, 1)
+		assertContains(t, stderr, ".env:1:1: missing '=' after key")
+	})
+}
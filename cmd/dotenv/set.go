@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/pechorka/dotenv"
+)
+
+// setCmd implements "dotenv set [-f file] KEY=VALUE...": it edits the file
+// in place, keeping comments and formatting, and creates it if needed.
+func setCmd(args []string, stdout, stderr io.Writer) error {
+	var file string
+	flags := newFlagSet("set", "KEY=VALUE...", stderr)
+	flags.StringVar(&file, "f", ".env", "edit `file`")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+
+	doc, err := openDocument(file)
+	if err != nil {
+		return err
+	}
+	for _, arg := range flags.Args() {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return fmt.Errorf("%q is not KEY=VALUE", arg)
+		}
+		if err := doc.Set(key, value); err != nil {
+			return err
+		}
+	}
+	return doc.Save(file)
+}
+
+// unsetCmd implements "dotenv unset [-f file] KEY...": it removes every
+// definition of the keys from the file. Keys that are not defined are
+// ignored.
+func unsetCmd(args []string, stdout, stderr io.Writer) error {
+	var file string
+	flags := newFlagSet("unset", "KEY...", stderr)
+	flags.StringVar(&file, "f", ".env", "edit `file`")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+
+	doc, err := openDocument(file)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for _, key := range flags.Args() {
+		changed = doc.Delete(key) || changed
+	}
+	if !changed {
+		return nil
+	}
+	return doc.Save(file)
+}
+
+// openDocument reads file for editing. A missing file yields an empty
+// document.
+func openDocument(file string) (*dotenv.Document, error) {
+	f, err := os.Open(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return dotenv.ParseDocument(strings.NewReader(""))
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return dotenv.ParseDocument(f)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_set(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".env": "# database\nDB_HOST=localhost # dev\nDB_PORT=5432\n",
+	})
+	read := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	t.Run("edits in place", func(t *testing.T) {
+		code, _, _ := runCLI(t, dir, "set", "DB_HOST=db", "NEW=with space")
+		assertEqual(t, code, 0)
+		assertEqual(t, read(".env"), "# database\nDB_HOST=db # dev\nDB_PORT=5432\nNEW='with space'\n")
+	})
+
+	t.Run("creates the file", func(t *testing.T) {
+		code, _, _ := runCLI(t, dir, "set", "-f", "new.env", "A=1")
+		assertEqual(t, code, 0)
+		assertEqual(t, read("new.env"), "A=1\n")
+	})
+
+	t.Run("rejects malformed arguments", func(t *testing.T) {
+		code, _, stderr := runCLI(t, dir, "set", "NOVALUE")
+		assertEqual(t, code, 1)
+		assertContains(t, stderr, `"NOVALUE" is not KEY=VALUE`)
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		code, _, _ := runCLI(t, dir, "unset", "DB_PORT", "UNKNOWN")
+		assertEqual(t, code, 0)
+		assertEqual(t, read(".env"), "# database\nDB_HOST=db # dev\nNEW='with space'\n")
+
+		code, _, _ = runCLI(t, dir, "unset", "-f", "missing.env", "A")
+		assertEqual(t, code, 0)
+		if _, err := os.Stat(filepath.Join(dir, "missing.env")); !os.IsNotExist(err) {
+			t.Fatalf("unset created missing.env: %v", err)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"time"
+
+	"github.com/pechorka/dotenv"
+)
+
+// stopTimeout is how long a command gets to exit after an interrupt before
+// it is killed for a restart.
+const stopTimeout = 5 * time.Second
+
+// watchChild runs the commands made by newCmd one after another, starting
+// a new one whenever a reload with opts changes the variables. A command
+// that exits on its own is restarted on the next change. An interrupt stops
+// the current command and ends watching with its exit status.
+func watchChild(newCmd func() (*exec.Cmd, error), opts []dotenv.Option, stderr io.Writer) error {
+	store, err := dotenv.NewStore(context.Background(), opts...)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	changes, unsubscribe := store.Subscribe()
+	defer unsubscribe()
+
+	signals := notifyInterrupts()
+	defer signal.Stop(signals)
+
+	var (
+		cmd    *exec.Cmd
+		done   chan error
+		status error
+	)
+	start := func() {
+		cmd, done = nil, nil
+		c, err := newCmd()
+		if err == nil {
+			err = c.Start()
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "dotenv run: %v; waiting for changes\n", err)
+			status = err
+			return
+		}
+		cmd, done = c, make(chan error, 1)
+		go func() { done <- c.Wait() }()
+	}
+	// stop interrupts the running command, kills it if it does not exit in
+	// time and waits for it.
+	stop := func(sig os.Signal) error {
+		if cmd.Process.Signal(sig) != nil {
+			cmd.Process.Kill()
+		}
+		select {
+		case err := <-done:
+			return exitStatus(err)
+		case <-time.After(stopTimeout):
+			cmd.Process.Kill()
+			return exitStatus(<-done)
+		}
+	}
+
+	start()
+	for {
+		select {
+		case sig := <-signals:
+			if cmd == nil {
+				return status
+			}
+			return stop(sig)
+		case _, ok := <-changes:
+			if !ok {
+				return status
+			}
+			if cmd != nil {
+				stop(os.Interrupt)
+			}
+			fmt.Fprintln(stderr, "dotenv run: variables changed; restarting")
+			start()
+		case err := <-done:
+			status = exitStatus(err)
+			cmd, done = nil, nil
+			msg := "command exited"
+			if status != nil {
+				msg += " with " + status.Error()
+			}
+			fmt.Fprintf(stderr, "dotenv run: %s; waiting for changes\n", msg)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes of a command
+// and reads of a test.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// waitFor polls until cond holds or fails the test after a few seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func Test_runWatch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sends an interrupt to the test process")
+	}
+	dir := writeFiles(t, map[string]string{".env": "WATCH_A=1\n"})
+	t.Chdir(dir)
+	t.Setenv("DOTENV_TEST_HELPER", "1")
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr syncBuffer
+	exited := make(chan int, 1)
+	go func() {
+		exited <- cli([]string{"run", "-watch", "-interval", "10ms", "--", self, "WATCH_A"}, &stdout, &stderr)
+	}()
+
+	waitFor(t, "first run", func() bool { return strings.Contains(stderr.String(), "waiting for changes") })
+	assertEqual(t, stdout.String(), "WATCH_A=1\n")
+
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("WATCH_A=2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "restart", func() bool { return strings.Count(stderr.String(), "waiting for changes") == 2 })
+	assertEqual(t, stdout.String(), "WATCH_A=1\nWATCH_A=2\n")
+	assertContains(t, stderr.String(), "variables changed; restarting")
+
+	proc, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := proc.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case code := <-exited:
+		if code != 0 {
+			t.Fatalf("exit code %d, stderr:\n%s", code, stderr.String())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("dotenv run -watch did not stop on interrupt")
+	}
+}
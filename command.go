@@ -0,0 +1,32 @@
+package dotenv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// CommandRunner executes the command of a $(command) substitution and returns
+// its output. It should give up once ctx is done, which happens when the
+// load is cancelled or WithTimeout expires. See WithCommandSubstitution.
+type CommandRunner func(ctx context.Context, command string) (string, error)
+
+// ShellRunner runs commands with "sh -c", killing them when ctx is done.
+// Standard error is included in the returned error when the command fails.
+func ShellRunner(ctx context.Context, command string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return "", err
+	}
+	return string(out), nil
+}
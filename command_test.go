@@ -0,0 +1,87 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func Test_commandSubstitution(t *testing.T) {
+	var ran []string
+	runner := func(_ context.Context, command string) (string, error) {
+		ran = append(ran, command)
+		if command == "fail" {
+			return "", errors.New("exit status 1")
+		}
+		return "out(" + command + ")\n", nil
+	}
+
+	t.Run("runs commands when enabled", func(t *testing.T) {
+		ran = nil
+		env, err := ParseString(`TOKEN=$(op read "x")
+NESTED="pre-$(echo $(date))-post"
+RAW='$(not run)'
+`, WithExpand(true), WithCommandSubstitution(runner))
+		assertNoError(t, err)
+		assertEqual(t, env["TOKEN"], `out(op read "x")`)
+		assertEqual(t, env["NESTED"], "pre-out(echo $(date))-post")
+		assertEqual(t, env["RAW"], "$(not run)")
+		assertEqual(t, len(ran), 2)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		env, err := ParseString("TOKEN=$(whoami)\n", WithExpand(true))
+		assertNoError(t, err)
+		assertEqual(t, env["TOKEN"], "$(whoami)")
+	})
+
+	t.Run("reports failures with location", func(t *testing.T) {
+		_, err := ParseString("A=1\nB=$(fail)\n", WithExpand(true), WithCommandSubstitution(runner))
+		if err == nil || !strings.HasPrefix(err.Error(), "<string>:2:3: command substitution $(fail)") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("shell runner", func(t *testing.T) {
+		out, err := ShellRunner(context.Background(), "printf 'hello\\n'")
+		assertNoError(t, err)
+		assertEqual(t, out, "hello\n")
+
+		_, err = ShellRunner(context.Background(), "echo oops >&2; exit 3")
+		if err == nil || !strings.Contains(err.Error(), "oops") {
+			t.Fatalf("expected stderr in error, got %v", err)
+		}
+	})
+}
+
+func Test_commandSubstitutionContext(t *testing.T) {
+	fs := fstest.MapFS{".env": &fstest.MapFile{Data: []byte("CMD_SLOW=$(sleep 10)\n")}}
+	noop := WithSetter(func(string, string) error { return nil })
+
+	t.Run("timeout kills the command", func(t *testing.T) {
+		start := time.Now()
+		err := Load(WithFs(fs), noop, WithExpand(true), WithCommandSubstitution(ShellRunner), WithTimeout(50*time.Millisecond))
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Fatalf("load took %v", elapsed)
+		}
+	})
+
+	t.Run("cancellation is passed to the runner", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		runner := func(ctx context.Context, command string) (string, error) {
+			cancel()
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		err := LoadContext(ctx, WithFs(fs), noop, WithExpand(true), WithCommandSubstitution(runner))
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	})
+}
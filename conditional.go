@@ -0,0 +1,89 @@
+package dotenv
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// WithConditionals enables conditional sections. Lines between
+//
+//	#if env=production
+//	...
+//	#endif
+//
+// only apply when env is "production". Conditions may list several names
+// (env=staging,production), be negated with !=, take an #else branch and
+// nest. Without WithConditionals the directives are plain comments. A typical
+// setup passes the same name as WithProfile. The systemd dialect does not
+// support conditionals.
+func WithConditionals(env string) Option {
+	return func(o *Options) {
+		o.Conditionals = true
+		o.ConditionEnv = env
+	}
+}
+
+// conditions tracks the #if blocks enclosing the current line.
+type conditions struct {
+	env string
+	// stack holds, per open block, whether its current branch applies and
+	// whether #else was seen.
+	stack []condFrame
+}
+
+type condFrame struct {
+	active  bool
+	hasElse bool
+}
+
+// active reports whether lines at the current position apply.
+func (c *conditions) active() bool {
+	return !slices.ContainsFunc(c.stack, func(f condFrame) bool { return !f.active })
+}
+
+// directive handles line if it is a conditional directive. It reports
+// whether it was one and, for malformed or misplaced directives, a reason.
+func (c *conditions) directive(line string) (bool, string) {
+	word, rest, _ := strings.Cut(strings.TrimSpace(line), " ")
+	switch word {
+	case "#if":
+		match, ok := c.eval(strings.TrimSpace(rest))
+		if !ok {
+			return true, fmt.Sprintf("unsupported condition %q, want env=NAME or env!=NAME", strings.TrimSpace(rest))
+		}
+		c.stack = append(c.stack, condFrame{active: match})
+	case "#else":
+		if len(c.stack) == 0 {
+			return true, "#else without #if"
+		}
+		top := &c.stack[len(c.stack)-1]
+		if top.hasElse {
+			return true, "duplicate #else"
+		}
+		top.active, top.hasElse = !top.active, true
+	case "#endif":
+		if len(c.stack) == 0 {
+			return true, "#endif without #if"
+		}
+		c.stack = c.stack[:len(c.stack)-1]
+	default:
+		return false, ""
+	}
+	return true, ""
+}
+
+func (c *conditions) eval(cond string) (match, ok bool) {
+	name, values, ok := strings.Cut(cond, "=")
+	negate := strings.HasSuffix(name, "!")
+	name = strings.TrimSpace(strings.TrimSuffix(name, "!"))
+	if !ok || name != "env" {
+		return false, false
+	}
+	for _, v := range strings.Split(values, ",") {
+		if strings.TrimSpace(v) == c.env {
+			return !negate, true
+		}
+	}
+	return negate, true
+}
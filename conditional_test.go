@@ -0,0 +1,69 @@
+package dotenv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_conditionals(t *testing.T) {
+	const content = `URL=http://localhost
+#if env=production
+URL=https://example.com
+#if env!=staging
+REPLICAS=3
+#endif
+#else
+DEBUG=1
+#endif
+#if env=staging,production
+CDN=on
+#endif
+`
+
+	t.Run("production", func(t *testing.T) {
+		env, err := ParseString(content, WithConditionals("production"))
+		assertNoError(t, err)
+		assertEqual(t, len(env), 3)
+		assertEqual(t, env["URL"], "https://example.com")
+		assertEqual(t, env["REPLICAS"], "3")
+		assertEqual(t, env["CDN"], "on")
+	})
+
+	t.Run("development", func(t *testing.T) {
+		env, err := ParseString(content, WithConditionals("development"))
+		assertNoError(t, err)
+		assertEqual(t, len(env), 2)
+		assertEqual(t, env["URL"], "http://localhost")
+		assertEqual(t, env["DEBUG"], "1")
+	})
+
+	t.Run("plain comments when disabled", func(t *testing.T) {
+		env, err := ParseString(content)
+		assertNoError(t, err)
+		assertEqual(t, env["URL"], "https://example.com")
+		assertEqual(t, env["DEBUG"], "1")
+	})
+
+	t.Run("document keeps skipped lines", func(t *testing.T) {
+		doc, err := ParseDocument(strings.NewReader(content), WithConditionals("production"))
+		assertNoError(t, err)
+		assertEqual(t, string(doc.Bytes()), content)
+	})
+
+	for _, tc := range []struct{ name, content, reason string }{
+		{"unterminated", "#if env=x\nA=1\n", "missing #endif"},
+		{"stray endif", "#endif\n", "#endif without #if"},
+		{"stray else", "#else\n", "#else without #if"},
+		{"bad condition", "#if profile=x\n#endif\n", `unsupported condition "profile=x", want env=NAME or env!=NAME`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseString(tc.content, WithConditionals("x"))
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected *ParseError, got %v", err)
+			}
+			assertEqual(t, perr.Reason, tc.reason)
+		})
+	}
+}
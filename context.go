@@ -0,0 +1,42 @@
+package dotenv
+
+import (
+	"context"
+	"io"
+	"io/fs"
+)
+
+// LoadContext works like Load but stops as soon as ctx is done. The context
+// is checked before every stat and open, between reads, and is passed to
+// providers, so a slow network mount or remote source can't hold up startup
+// past its deadline. The error then wraps ctx.Err().
+func LoadContext(ctx context.Context, userOptions ...Option) error {
+	_, _, err := load(ctx, userOptions)
+	return err
+}
+
+// contextReader fails reads once ctx is done.
+type contextReader struct {
+	r   io.Reader
+	ctx context.Context
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// contextFile is an fs.File whose reads fail once ctx is done.
+type contextFile struct {
+	fs.File
+	ctx context.Context
+}
+
+func (f contextFile) Read(p []byte) (int, error) {
+	if err := f.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return f.File.Read(p)
+}
@@ -0,0 +1,58 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func Test_loadContext(t *testing.T) {
+	fs := fstest.MapFS{".env": &fstest.MapFile{Data: []byte("CTX_A=1\n")}}
+	noop := WithSetter(func(string, string) error { return nil })
+
+	t.Run("loads", func(t *testing.T) {
+		var got map[string]string
+		setter := WithSetter(func(k, v string) error {
+			if got == nil {
+				got = map[string]string{}
+			}
+			got[k] = v
+			return nil
+		})
+		assertNoError(t, LoadContext(context.Background(), WithFs(fs), setter))
+		assertEqual(t, got["CTX_A"], "1")
+	})
+
+	t.Run("canceled before stat", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := LoadContext(ctx, WithFs(fs), noop)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("want context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("canceled while reading", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := parseReader(ctx, strings.NewReader("A=1\n"), "r", nil)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("want context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("providers get the context", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		var hasDeadline bool
+		provider := ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+			_, hasDeadline = ctx.Deadline()
+			return nil, ctx.Err()
+		})
+		assertNoError(t, LoadContext(ctx, WithFs(fs), WithProviders(provider), noop))
+		assertEqual(t, hasDeadline, true)
+	})
+}
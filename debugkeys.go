@@ -0,0 +1,34 @@
+package dotenv
+
+// WithDebugKeys logs every variable Load sets or unsets, with the file and
+// line it came from and whether it replaced a value already in the process
+// environment, to diagnose why a variable ends up with an unexpected value.
+// Values are left out unless WithUnsafeValueLogging is used too.
+func WithDebugKeys() Option {
+	return func(o *Options) {
+		o.DebugKeys = true
+	}
+}
+
+// WithUnsafeValueLogging adds values to the messages of WithDebugKeys. Only
+// use it locally: values often hold secrets that must not reach log storage.
+func WithUnsafeValueLogging() Option {
+	return func(o *Options) {
+		o.UnsafeValueLogging = true
+	}
+}
+
+// logKey logs a variable handled by Load under WithDebugKeys.
+func (o Options) logKey(msg string, kr KeyReport, value string) {
+	if !o.DebugKeys {
+		return
+	}
+	args := []any{"key", kr.Key, "file", kr.File, "line", kr.Line, "overrode_env", kr.OverrodeEnv}
+	if len(kr.Shadowed) > 0 {
+		args = append(args, "shadowed", kr.Shadowed)
+	}
+	if o.UnsafeValueLogging {
+		args = append(args, "value", value)
+	}
+	o.Logger.Info(msg, args...)
+}
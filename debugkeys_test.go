@@ -0,0 +1,46 @@
+package dotenv
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_debugKeys(t *testing.T) {
+	fs := fstest.MapFS{
+		".env":       &fstest.MapFile{Data: []byte("DBG_A=first\nDBG_B=\n")},
+		".env.local": &fstest.MapFile{Data: []byte("DBG_A=s3cr3t\n")},
+	}
+	noop := func(o *Options) {
+		WithSetter(func(string, string) error { return nil })(o)
+		WithUnsetter(func(string) error { return nil })(o)
+	}
+	t.Setenv("DBG_B", "x")
+
+	logger := &testLogger{}
+	_, err := LoadReport(WithFs(fs), WithPaths(".env", ".env.local"), noop, WithEmptyValues(EmptyUnset), WithDebugKeys(), WithLogger(logger))
+	assertNoError(t, err)
+	logs := logger.String()
+	for _, want := range []string{"variable set", "DBG_A", ".env.local", "variable unset", "DBG_B"} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("missing %q in %q", want, logs)
+		}
+	}
+	if strings.Contains(logs, "s3cr3t") {
+		t.Fatalf("value leaked into logs: %q", logs)
+	}
+
+	logger = &testLogger{}
+	_, err = LoadReport(WithFs(fs), WithPaths(".env", ".env.local"), noop, WithDebugKeys(), WithUnsafeValueLogging(), WithLogger(logger))
+	assertNoError(t, err)
+	if !strings.Contains(logger.String(), "s3cr3t") {
+		t.Fatalf("value missing from logs: %q", logger.String())
+	}
+
+	logger = &testLogger{}
+	_, err = LoadReport(WithFs(fs), noop, WithLogger(logger))
+	assertNoError(t, err)
+	if strings.Contains(logger.String(), "variable set") {
+		t.Fatalf("keys logged without WithDebugKeys: %q", logger.String())
+	}
+}
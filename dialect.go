@@ -0,0 +1,204 @@
+package dotenv
+
+import (
+	"io"
+	"strings"
+)
+
+// Dialect selects the syntax rules used to parse dotenv files.
+type Dialect int
+
+const (
+	// DialectDefault is the syntax described in the package documentation.
+	DialectDefault Dialect = iota
+	// DialectDocker matches `docker run --env-file`: everything after the
+	// first '=' is the value, verbatim. Quotes are not removed, escapes and
+	// inline comments are not interpreted and values can't span lines. Only
+	// leading whitespace is trimmed, an "export" keyword is not recognized
+	// and keys containing whitespace, like "export KEY", are an error even
+	// without WithStrict. A line holding just a key takes the value of that
+	// variable from the process environment, or is dropped if it is not set.
+	DialectDocker
+	// DialectGodotenv matches github.com/joho/godotenv: "KEY: value" is
+	// accepted next to KEY=value, double-quoted values interpret \n and \r
+	// and drop the backslash of any other escape except \$, and unquoted and
+	// double-quoted values are expanded as with WithExpand(true).
+	DialectGodotenv
+	// DialectSystemd matches systemd's EnvironmentFile=: lines starting with
+	// '#' or ';' are comments, a trailing backslash continues the line, quotes
+	// only open at the start of a value and adjacent quoted parts are
+	// concatenated, and inside double quotes only \", \\, \` and \$ are
+	// escapes. There are no inline comments, no "export" keyword and keys
+	// must be valid shell variable names.
+	DialectSystemd
+	// DialectINI reads INI-style files: "[section]" lines start a section
+	// and keys inside it are exported as SECTION_KEY, upper-cased with
+	// NormalizeUpperSnake, so "[database]" followed by "host=x" yields
+	// DATABASE_HOST=x. Keys before the first section keep their names. Lines
+	// starting with '#' or ';' are comments; values are trimmed and unquoted
+	// as in DialectDefault.
+	DialectINI
+)
+
+// WithDialect selects the syntax rules used for parsing, DialectDefault
+// unless set.
+func WithDialect(d Dialect) Option {
+	return func(o *Options) {
+		o.Dialect = d
+	}
+}
+
+// ParserConfig describes the syntax rules of a dialect. Start from a
+// built-in dialect with Dialect.ParserConfig and adjust it, then pass it to
+// WithParserConfig.
+type ParserConfig struct {
+	// CommentPrefixes start comment lines (after leading whitespace).
+	CommentPrefixes []string
+	// Separators lists the characters that may separate a key from its
+	// value; the first one found on a line is used.
+	Separators string
+	// TrimSpace trims whitespace around keys and values. Otherwise only the
+	// whitespace at the start of a line is dropped.
+	TrimSpace bool
+	// Quotes removes single or double quotes around values and lets quoted
+	// values span several lines.
+	Quotes bool
+	// Unescape interprets escapes inside double-quoted values; nil keeps them
+	// literal. UnescapeStandard is what WithEscapes uses.
+	Unescape func(string) string
+	// Expand enables expansion as with WithExpand(true).
+	Expand bool
+	// InlineComments strips " #" comments after values.
+	InlineComments bool
+	// ExportPrefix ignores a leading "export" keyword.
+	ExportPrefix bool
+	// InheritBareKeys makes a line holding just a key take the value of that
+	// variable from the process environment.
+	InheritBareKeys bool
+	// Sections treats "[name]" lines as section headers that prefix the keys
+	// below them, as described for DialectINI. An empty "[]" ends the
+	// current section.
+	Sections bool
+
+	// invalidKeyChar reports the first character rejected in strict mode;
+	// nil means invalidKeyChar.
+	invalidKeyChar func(key string) int
+	// rejectInvalidKeys applies invalidKeyChar outside strict mode too,
+	// failing the file instead of reporting the problem with the others.
+	rejectInvalidKeys bool
+	// scan replaces the line based scanner entirely when set.
+	scan func(opts Options, r io.Reader, envPath string, fn func(statement) error) error
+}
+
+// WithParserConfig parses with a custom set of syntax rules instead of a
+// built-in dialect. It takes precedence over WithDialect.
+func WithParserConfig(cfg ParserConfig) Option {
+	return func(o *Options) {
+		o.ParserConfig = &cfg
+	}
+}
+
+// UnescapeStandard interprets \n, \t, \r, \" and \\ and keeps any other
+// escape verbatim, so that "\$" still reaches expansion.
+func UnescapeStandard(s string) string {
+	return unescape(s)
+}
+
+// ParserConfig returns the syntax rules of d.
+func (d Dialect) ParserConfig() ParserConfig {
+	switch d {
+	case DialectDocker:
+		return ParserConfig{
+			CommentPrefixes: []string{"#"},
+			Separators:      "=",
+			InheritBareKeys: true,
+			invalidKeyChar: func(key string) int {
+				return strings.IndexAny(key, " \t")
+			},
+			rejectInvalidKeys: true,
+		}
+	case DialectSystemd:
+		return ParserConfig{
+			CommentPrefixes: []string{"#", ";"},
+			Separators:      "=",
+			invalidKeyChar:  systemdInvalidKeyChar,
+			scan:            scanSystemd,
+		}
+	case DialectINI:
+		return ParserConfig{
+			CommentPrefixes: []string{"#", ";"},
+			Separators:      "=",
+			TrimSpace:       true,
+			Quotes:          true,
+			InlineComments:  true,
+			Sections:        true,
+		}
+	case DialectGodotenv:
+		cfg := DialectDefault.ParserConfig()
+		cfg.Separators = "=:"
+		cfg.Unescape = unescapeGodotenv
+		cfg.Expand = true
+		return cfg
+	default:
+		return ParserConfig{
+			CommentPrefixes: []string{"#"},
+			Separators:      "=",
+			TrimSpace:       true,
+			Quotes:          true,
+			InlineComments:  true,
+			ExportPrefix:    true,
+		}
+	}
+}
+
+// parserConfig returns the syntax rules selected by the options.
+func (o Options) parserConfig() ParserConfig {
+	if o.ParserConfig != nil {
+		return *o.ParserConfig
+	}
+	return o.Dialect.ParserConfig()
+}
+
+func (c ParserConfig) keyCheck() func(string) int {
+	if c.invalidKeyChar != nil {
+		return c.invalidKeyChar
+	}
+	return invalidKeyChar
+}
+
+func (c ParserConfig) isComment(line string) bool {
+	for _, prefix := range c.CommentPrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// unescapeGodotenv mirrors godotenv: \n and \r become control characters,
+// \$ is left for expansion and any other backslash is dropped.
+func unescapeGodotenv(s string) string {
+	if !strings.ContainsRune(s, '\\') {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case '$':
+			b.WriteString(`\$`)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,154 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func Test_dialectDocker(t *testing.T) {
+	t.Setenv("DOCKER_INHERITED", "from-env")
+	os.Unsetenv("DOCKER_UNSET")
+
+	env, err := ParseString(`# comment
+  QUOTED="keep quotes"
+SINGLE='single'
+`+"SPACES=  padded  \n"+`COMMENT=value # not a comment
+ESCAPES="a\nb"
+DOCKER_INHERITED
+DOCKER_UNSET
+EQUALS=a=b
+`, WithDialect(DialectDocker))
+	assertNoError(t, err)
+	assertEqual(t, env["QUOTED"], `"keep quotes"`)
+	assertEqual(t, env["SINGLE"], `'single'`)
+	assertEqual(t, env["SPACES"], "  padded  ")
+	assertEqual(t, env["COMMENT"], "value # not a comment")
+	assertEqual(t, env["ESCAPES"], `"a\nb"`)
+	assertEqual(t, env["DOCKER_INHERITED"], "from-env")
+	assertEqual(t, env["EQUALS"], "a=b")
+	_, ok := env["DOCKER_UNSET"]
+	assertEqual(t, ok, false)
+
+	for _, opts := range [][]Option{{WithDialect(DialectDocker)}, {WithDialect(DialectDocker), WithStrict()}} {
+		for _, content := range []string{"export EXPORTED=1\n", "A=1\nexport L\n", "KEY WITH SPACE=1\n"} {
+			_, err = ParseString(content, opts...)
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected *ParseError for whitespace in key of %q, got %v", content, err)
+			}
+		}
+	}
+	_, err = ParseString("export EXPORTED=1\n", WithDialect(DialectDocker))
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected *ParseError, got %v", err)
+	}
+	assertEqual(t, perr.Col, 7)
+}
+
+func Test_dialectGodotenv(t *testing.T) {
+	t.Setenv("GODOTENV_HOME", "/home/me")
+
+	env, err := ParseString(`export HOST: localhost
+PORT=8080 # comment
+URL="http://${HOST}:$PORT"
+ESCAPED="a\nb\tc\"d\\e\$HOST"
+RAW='$HOST\n'
+DIR=$GODOTENV_HOME/app
+`, WithDialect(DialectGodotenv))
+	assertNoError(t, err)
+	assertEqual(t, env["HOST"], "localhost")
+	assertEqual(t, env["PORT"], "8080")
+	assertEqual(t, env["URL"], "http://localhost:8080")
+	assertEqual(t, env["ESCAPED"], "a\nbtc\"d\\e$HOST")
+	assertEqual(t, env["RAW"], `$HOST\n`)
+	assertEqual(t, env["DIR"], "/home/me/app")
+}
+
+func Test_dialectSystemd(t *testing.T) {
+	content := `# comment \
+continued comment
+; also a comment
+` + "  KEY = value with spaces   \n" + `CONT=first \
+second
+DQ="a \"b\" \$c \n d"
+SQ='single
+multi'
+CONCAT="one" 'two' three
+HASH=value # not a comment
+export INVALID=1
+LAST=end`
+
+	env, err := ParseString(content, WithDialect(DialectSystemd))
+	assertNoError(t, err)
+	assertEqual(t, env["KEY"], "value with spaces")
+	assertEqual(t, env["CONT"], "first second")
+	assertEqual(t, env["DQ"], `a "b" $c \n d`)
+	assertEqual(t, env["SQ"], "single\nmulti")
+	assertEqual(t, env["CONCAT"], "onetwothree")
+	assertEqual(t, env["HASH"], "value # not a comment")
+	assertEqual(t, env["LAST"], "end")
+	assertEqual(t, len(env), 7)
+
+	_, err = ParseString("A=1\nexport INVALID=1\n", WithDialect(DialectSystemd), WithStrict())
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected *ParseError, got %v", err)
+	}
+	assertEqual(t, *perr, ParseError{File: "<string>", Line: 2, Col: 7, Reason: `invalid character ' ' in key`})
+
+	doc, err := ParseDocument(strings.NewReader(content), WithDialect(DialectSystemd))
+	assertNoError(t, err)
+	assertEqual(t, string(doc.Bytes()), content+"\n")
+	sq, _ := doc.Get("SQ")
+	assertEqual(t, sq, "single\nmulti")
+}
+
+func Test_parserConfig(t *testing.T) {
+	cfg := DialectDefault.ParserConfig()
+	cfg.CommentPrefixes = []string{"//", "#"}
+	cfg.Separators = ":"
+	cfg.InlineComments = false
+	cfg.ExportPrefix = false
+	cfg.Unescape = UnescapeStandard
+
+	env, err := ParseString(`// comment
+# comment
+HOST: localhost # kept
+MSG: "a\tb"
+URL: http://x=y
+export A: 1
+`, WithParserConfig(cfg), WithDialect(DialectDocker))
+	assertNoError(t, err)
+	assertEqual(t, env["HOST"], "localhost # kept")
+	assertEqual(t, env["MSG"], "a\tb")
+	assertEqual(t, env["URL"], "http://x=y")
+	assertEqual(t, env["export A"], "1")
+	assertEqual(t, len(env), 4)
+}
+
+func Test_dialectINI(t *testing.T) {
+	const content = `; legacy settings
+name = app
+
+[database]
+host = db.local
+port=5432 # default
+
+[feature-flags]
+new.ui = "on"
+
+[]
+after = x
+`
+	env, err := ParseString(content, WithDialect(DialectINI))
+	assertNoError(t, err)
+	assertEqual(t, len(env), 5)
+	assertEqual(t, env["name"], "app")
+	assertEqual(t, env["DATABASE_HOST"], "db.local")
+	assertEqual(t, env["DATABASE_PORT"], "5432")
+	assertEqual(t, env["FEATURE_FLAGS_NEW_UI"], "on")
+	assertEqual(t, env["after"], "x")
+}
@@ -0,0 +1,70 @@
+package dotenv
+
+import (
+	"maps"
+	"slices"
+)
+
+// Changes describes how one set of values differs from another. Each list
+// is sorted by key.
+type Changes struct {
+	// Added lists keys only in the new values; Old is empty.
+	Added []KeyChange
+	// Removed lists keys only in the old values; New is empty.
+	Removed []KeyChange
+	// Changed lists keys whose value differs.
+	Changed []KeyChange
+}
+
+// KeyChange is the old and new value of a key.
+type KeyChange struct {
+	Key string
+	Old string
+	New string
+}
+
+// Empty reports whether there are no changes.
+func (c Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// Diff compares the values in a with the values in b.
+func Diff(a, b map[string]string) Changes {
+	var c Changes
+	for _, key := range slices.Sorted(maps.Keys(a)) {
+		old := a[key]
+		val, ok := b[key]
+		switch {
+		case !ok:
+			c.Removed = append(c.Removed, KeyChange{Key: key, Old: old})
+		case val != old:
+			c.Changed = append(c.Changed, KeyChange{Key: key, Old: old, New: val})
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(b)) {
+		if _, ok := a[key]; !ok {
+			c.Added = append(c.Added, KeyChange{Key: key, New: b[key]})
+		}
+	}
+	return c
+}
+
+// DiffFiles parses the files or directories a and b with the given options,
+// replacing any paths, and compares their values.
+func DiffFiles(a, b string, userOptions ...Option) (Changes, error) {
+	userOptions = slices.Clip(userOptions)
+	old, err := Parse(append(userOptions, WithPaths(a))...)
+	if err != nil {
+		return Changes{}, err
+	}
+	val, err := Parse(append(userOptions, WithPaths(b))...)
+	if err != nil {
+		return Changes{}, err
+	}
+	return Diff(old, val), nil
+}
+
+// Diff returns the changes between the old and new snapshot.
+func (c Change) Diff() Changes {
+	return Diff(c.Old.values, c.New.values)
+}
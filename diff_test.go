@@ -0,0 +1,40 @@
+package dotenv
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func Test_diff(t *testing.T) {
+	c := Diff(
+		map[string]string{"KEEP": "1", "GONE": "x", "MOVED": "a", "B": "1"},
+		map[string]string{"KEEP": "1", "NEW": "y", "MOVED": "b", "A": "2", "B": "2"},
+	)
+	assertEqual(t, len(c.Added), 2)
+	assertEqual(t, c.Added[0], KeyChange{Key: "A", New: "2"})
+	assertEqual(t, c.Added[1], KeyChange{Key: "NEW", New: "y"})
+	assertEqual(t, len(c.Removed), 1)
+	assertEqual(t, c.Removed[0], KeyChange{Key: "GONE", Old: "x"})
+	assertEqual(t, len(c.Changed), 2)
+	assertEqual(t, c.Changed[0], KeyChange{Key: "B", Old: "1", New: "2"})
+	assertEqual(t, c.Changed[1], KeyChange{Key: "MOVED", Old: "a", New: "b"})
+	assertEqual(t, c.Empty(), false)
+	assertEqual(t, Diff(map[string]string{"A": "1"}, map[string]string{"A": "1"}).Empty(), true)
+
+	fs := fstest.MapFS{
+		".env":            &fstest.MapFile{Data: []byte("A=1\nB=${A}\n")},
+		".env.production": &fstest.MapFile{Data: []byte("A=2\nB=${A}\n")},
+	}
+	c, err := DiffFiles(".env", ".env.production", WithFs(fs), WithExpand(true))
+	assertNoError(t, err)
+	assertEqual(t, len(c.Changed), 2)
+	assertEqual(t, c.Changed[1], KeyChange{Key: "B", Old: "1", New: "2"})
+
+	_, err = DiffFiles(".env", "missing", WithFs(fs), WithRequiredPaths())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	change := Change{Old: NewSnapshot(map[string]string{"A": "1"}), New: NewSnapshot(map[string]string{"A": "2"})}
+	assertEqual(t, change.Diff().Changed[0], KeyChange{Key: "A", Old: "1", New: "2"})
+}
@@ -0,0 +1,222 @@
+package dotenv
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Document is a dotenv file kept in its original shape: comments, blank
+// lines, ordering and formatting survive edits, and only the entries that
+// are changed get rewritten.
+type Document struct {
+	stmts []statement
+}
+
+// ParseDocument reads a dotenv file into a Document. Parser options such as
+// WithEscapes and WithStrict apply; values are never expanded.
+func ParseDocument(r io.Reader, userOptions ...Option) (*Document, error) {
+	opts := applyOptions(userOptions)
+	doc := &Document{}
+	err := scanStatements(opts, r, "<document>", func(st statement) error {
+		doc.stmts = append(doc.stmts, st)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Keys returns the keys defined in the document in order of first appearance.
+func (d *Document) Keys() []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, st := range d.stmts {
+		if st.key != "" && !seen[st.key] {
+			seen[st.key] = true
+			keys = append(keys, st.key)
+		}
+	}
+	return keys
+}
+
+// Get returns the value of key. As when parsing, the last definition wins.
+func (d *Document) Get(key string) (string, bool) {
+	if i := d.lookup(key); i >= 0 {
+		return d.stmts[i].value, true
+	}
+	return "", false
+}
+
+// Set updates the last definition of key, keeping its indentation, export
+// keyword and inline comment, or appends a new entry when key is not defined.
+func (d *Document) Set(key, value string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	i := d.lookup(key)
+	if i < 0 {
+		if n := len(d.stmts); n > 0 && d.stmts[n-1].section != "" {
+			// End the INI section so that the new key isn't prefixed.
+			d.stmts = append(d.stmts, statement{raw: "[]"})
+		}
+		d.stmts = append(d.stmts, statement{key: key})
+		i = len(d.stmts) - 1
+	}
+	st := &d.stmts[i]
+	quoted := quoteValue(value)
+	st.value = value
+	st.quote = 0
+	if quoted != value {
+		st.quote = quoted[0]
+	}
+	written := st.writtenKey()
+	st.raw = st.prefix + written + "=" + quoted + st.comment
+	st.valueCol = len(st.prefix) + len(written) + 2
+	return nil
+}
+
+// Delete removes every definition of key and reports whether any existed.
+func (d *Document) Delete(key string) bool {
+	n := len(d.stmts)
+	d.stmts = deleteStatements(d.stmts, key)
+	return len(d.stmts) != n
+}
+
+// Rename changes the key of every definition of oldKey to newKey, leaving
+// the rest of each line untouched. It fails when oldKey is not defined or
+// newKey already is. Keys inside an INI section can only be renamed within
+// that section, DB_HOST to DB_ADDR for "[db]".
+func (d *Document) Rename(oldKey, newKey string) error {
+	if err := validateKey(newKey); err != nil {
+		return err
+	}
+	if d.lookup(oldKey) < 0 {
+		return fmt.Errorf("rename: key %s not found", oldKey)
+	}
+	if d.lookup(newKey) >= 0 {
+		return fmt.Errorf("rename: key %s already exists", newKey)
+	}
+
+	written := make(map[int]string)
+	for i, st := range d.stmts {
+		if st.key != oldKey {
+			continue
+		}
+		w, err := sectionKey(st, newKey)
+		if err != nil {
+			return fmt.Errorf("rename: %w", err)
+		}
+		written[i] = w
+	}
+	for i, w := range written {
+		st := &d.stmts[i]
+		old := st.writtenKey()
+		st.raw = st.prefix + w + st.raw[len(st.prefix)+len(old):]
+		st.key = newKey
+		if st.rawKey != "" {
+			st.rawKey = w
+		}
+		st.valueCol += len(w) - len(old)
+	}
+	return nil
+}
+
+// sectionKey returns how key is written in the INI section of st.
+func sectionKey(st statement, key string) (string, error) {
+	if st.rawKey == "" {
+		return key, nil
+	}
+	w, ok := strings.CutPrefix(key, st.section+"_")
+	if !ok || w == "" || NormalizeUpperSnake(w) != w {
+		return "", fmt.Errorf("key %s can't be written in section %s", key, st.section)
+	}
+	return w, nil
+}
+
+// Entry is a statement of a Document as written in the file.
+type Entry struct {
+	// Line is the 1-based number of the first physical line.
+	Line int
+	// Raw holds the physical lines of the statement joined with '\n'.
+	Raw string
+	// Key is empty for blank lines, comments and malformed lines.
+	Key string
+	// RawKey is Key as written in Raw. They differ for keys in INI
+	// sections, such as "host" for DB_HOST.
+	RawKey string
+	Value  string
+	// Quote is the quote character around the value, or 0 if unquoted.
+	Quote byte
+	// ValueCol is the 1-based column the value starts at.
+	ValueCol int
+	// Comment is a trailing inline comment including the whitespace in
+	// front of it.
+	Comment string
+}
+
+// Entries returns the statements of the document in order, including blank
+// lines and comments, for tools that inspect how a file is written.
+func (d *Document) Entries() []Entry {
+	entries := make([]Entry, len(d.stmts))
+	for i, st := range d.stmts {
+		entries[i] = Entry{
+			Line:     st.line,
+			Raw:      st.raw,
+			Key:      st.key,
+			RawKey:   st.writtenKey(),
+			Value:    st.value,
+			Quote:    st.quote,
+			ValueCol: st.valueCol,
+			Comment:  st.comment,
+		}
+	}
+	return entries
+}
+
+// Bytes returns the document in dotenv format.
+func (d *Document) Bytes() []byte {
+	var b strings.Builder
+	for _, st := range d.stmts {
+		b.WriteString(st.raw)
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
+
+// WriteTo writes the document to w.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(d.Bytes())
+	return int64(n), err
+}
+
+func (d *Document) lookup(key string) int {
+	for i := len(d.stmts) - 1; i >= 0; i-- {
+		if d.stmts[i].key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func deleteStatements(stmts []statement, key string) []statement {
+	kept := stmts[:0]
+	for _, st := range stmts {
+		if st.key != key {
+			kept = append(kept, st)
+		}
+	}
+	return kept
+}
+
+func validateKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("empty key")
+	}
+	if i := invalidKeyChar(key); i >= 0 {
+		return fmt.Errorf("invalid character %q in key %q", key[i], key)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package dotenv
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const documentFixture = `# database
+export DB_HOST=localhost # local only
+
+DB_PASS='s3cret'
+CERT="line1
+line2"
+  INDENTED = yes
+not a valid line
+`
+
+func Test_document(t *testing.T) {
+	t.Run("round trips unchanged", func(t *testing.T) {
+		doc, err := ParseDocument(strings.NewReader(documentFixture))
+		assertNoError(t, err)
+		assertEqual(t, string(doc.Bytes()), documentFixture)
+		assertEqual(t, strings.Join(doc.Keys(), ","), "DB_HOST,DB_PASS,CERT,INDENTED")
+
+		cert, ok := doc.Get("CERT")
+		assertEqual(t, ok, true)
+		assertEqual(t, cert, "line1\nline2")
+	})
+
+	t.Run("set keeps formatting", func(t *testing.T) {
+		doc, err := ParseDocument(strings.NewReader(documentFixture))
+		assertNoError(t, err)
+		assertNoError(t, doc.Set("DB_HOST", "db.internal"))
+		assertNoError(t, doc.Set("CERT", "single"))
+		assertNoError(t, doc.Set("NEW_KEY", "hello world"))
+		assertEqual(t, string(doc.Bytes()), `# database
+export DB_HOST=db.internal # local only
+
+DB_PASS='s3cret'
+CERT=single
+  INDENTED = yes
+not a valid line
+NEW_KEY='hello world'
+`)
+	})
+
+	t.Run("delete and rename", func(t *testing.T) {
+		doc, err := ParseDocument(strings.NewReader(documentFixture))
+		assertNoError(t, err)
+		assertEqual(t, doc.Delete("CERT"), true)
+		assertEqual(t, doc.Delete("CERT"), false)
+		assertNoError(t, doc.Rename("INDENTED", "SPACED"))
+		assertNoError(t, doc.Rename("DB_HOST", "DATABASE_HOST"))
+		if err := doc.Rename("MISSING", "X"); err == nil {
+			t.Fatal("expected error renaming missing key")
+		}
+		if err := doc.Rename("SPACED", "DB_PASS"); err == nil {
+			t.Fatal("expected error renaming onto existing key")
+		}
+		assertEqual(t, string(doc.Bytes()), `# database
+export DATABASE_HOST=localhost # local only
+
+DB_PASS='s3cret'
+  SPACED = yes
+not a valid line
+`)
+	})
+	t.Run("entries", func(t *testing.T) {
+		doc, err := ParseDocument(strings.NewReader(documentFixture))
+		assertNoError(t, err)
+		entries := doc.Entries()
+		assertEqual(t, len(entries), 7)
+		assertEqual(t, entries[1], Entry{
+			Line: 2, Raw: "export DB_HOST=localhost # local only", Key: "DB_HOST", RawKey: "DB_HOST",
+			Value: "localhost", ValueCol: 16, Comment: " # local only",
+		})
+		assertEqual(t, entries[3].Quote, byte('\''))
+		assertEqual(t, entries[4].Line, 5)
+		assertEqual(t, entries[5].Line, 7)
+		assertEqual(t, entries[6].Key, "")
+	})
+}
+
+func Test_documentSections(t *testing.T) {
+	const content = "top=1\n[db]\nhost = x\n"
+	parse := func(t *testing.T) *Document {
+		t.Helper()
+		doc, err := ParseDocument(strings.NewReader(content), WithDialect(DialectINI))
+		assertNoError(t, err)
+		return doc
+	}
+	reparse := func(t *testing.T, doc *Document) map[string]string {
+		t.Helper()
+		env, err := ParseReader(bytes.NewReader(doc.Bytes()), WithDialect(DialectINI))
+		assertNoError(t, err)
+		return env
+	}
+
+	t.Run("set", func(t *testing.T) {
+		doc := parse(t)
+		assertNoError(t, doc.Set("DB_HOST", "y"))
+		assertNoError(t, doc.Set("NEW", "z"))
+		assertEqual(t, string(doc.Bytes()), "top=1\n[db]\nhost=y\n[]\nNEW=z\n")
+		env := reparse(t, doc)
+		assertEqual(t, len(env), 3)
+		assertEqual(t, env["DB_HOST"], "y")
+		assertEqual(t, env["NEW"], "z")
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		doc := parse(t)
+		assertNoError(t, doc.Rename("DB_HOST", "DB_ADDR"))
+		assertEqual(t, string(doc.Bytes()), "top=1\n[db]\nADDR = x\n")
+		assertEqual(t, reparse(t, doc)["DB_ADDR"], "x")
+		assertNoError(t, doc.Set("DB_ADDR", "y"))
+		assertEqual(t, reparse(t, doc)["DB_ADDR"], "y")
+
+		if err := doc.Rename("DB_ADDR", "HOST"); err == nil {
+			t.Fatal("expected error for renaming out of the section")
+		}
+		assertEqual(t, reparse(t, doc)["DB_ADDR"], "y")
+	})
+}
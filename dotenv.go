@@ -1,39 +1,151 @@
 // Package dotenv provides a tiny, predictable loader for .env files.
 //
 // It reads simple KEY=VALUE lines, ignoring blank lines and lines starting
-// with '#'. Optional single or double quotes around values are trimmed.
+// with '#'. A leading "export " is ignored. Optional single or double quotes
+// around values are trimmed, and a " #" after an unquoted value starts a
+// comment.
+// Quoted values may span several lines until the closing quote.
 // When multiple paths are provided, later ones override earlier ones. If a
-// provided path is a directory, ".env" is joined to it.
+// provided path is a directory, ".env" (or the names set via WithFilename) is
+// joined to it.
 package dotenv
 
 import (
-	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
 	"log/slog"
 	"os"
 	"path"
-	"strings"
+	"regexp"
+	"time"
 )
 
 type Options struct {
-	Paths  []string
-	RootFs fs.FS
-	Logger Logger
+	Paths        []string
+	Filenames    []string
+	RootFs       fs.FS
+	Logger       Logger
+	Expand       bool
+	Escapes      bool
+	NoOverride   bool
+	Strict       bool
+	RequirePaths bool
+	Profile      string
+	SearchUp     bool
+	Setter       func(key, value string) error
+	Unsetter     func(key string) error
+	Required     []string
+	Schema       *Schema
+	ExpandDepth  int
+
+	CommandRunner CommandRunner
+	Dialect       Dialect
+	ParserConfig  *ParserConfig
+	EmptyValues   EmptyValues
+	Duplicates    DuplicatePolicy
+	MaxLineLength int
+	MaxFileSize   int64
+	MaxKeys       int
+	Encoding      Encoding
+	KeyNormalizer func(key string) string
+	Prefix        string
+	StripPrefix   string
+	OnlyPrefix    string
+	AllowKeys     []string
+	DenyKeys      []string
+	AllowPatterns []*regexp.Regexp
+	DenyPatterns  []*regexp.Regexp
+	ValueHooks    []ValueHook
+
+	FileIndirection bool
+	Base64Values    bool
+	JSONValues      bool
+	Includes        bool
+	Conditionals    bool
+	ConditionEnv    string
+	Format          Format
+	KeyDirs         []string
+	Providers       []Provider
+	Retries         int
+	RetryBackoff    time.Duration
+	Timeout         time.Duration
+	Concurrency     int
+	Vault           bool
+	VaultKey        string
+	Decryption      bool
+	PrivateKeys     []string
+	KeyProvider     KeyProvider
+
+	StrictPermissions bool
+	PermissionMask    fs.FileMode
+	SecretRedaction   bool
+	VerifyLock        bool
+	Tracer            Tracer
+	Metrics           Metrics
+
+	DebugKeys          bool
+	UnsafeValueLogging bool
+	DryRun             bool
+	ReloadInterval     time.Duration
+	Sync               bool
+
+	// fileReports collects the sources read for LoadReport.
+	fileReports *[]FileReport
+	// lock verifies the files read, including included ones, when
+	// WithVerifyLock is used.
+	lock *lockVerifier
+	// customSetter is set by WithSetter: values do not go to the process
+	// environment.
+	customSetter bool
 }
 
 type Option func(*Options)
 
 // WithPaths sets candidate paths (files or directories) to read, in order.
-// If a path is a directory, ".env" is joined. When multiple paths are
-// provided, later ones override earlier ones.
+// If a path is a directory, ".env" (see WithFilename) is joined. When
+// multiple paths are provided, later ones override earlier ones.
 func WithPaths(paths ...string) Option {
 	return func(o *Options) {
 		o.Paths = paths
 	}
 }
 
+// WithFilename sets the file names looked up when a path is a directory.
+// Names are tried in order and the first existing file is read. Defaults to
+// ".env".
+func WithFilename(names ...string) Option {
+	return func(o *Options) {
+		o.Filenames = names
+	}
+}
+
+// WithProfile loads the standard layering for the given profile instead of a
+// single file per path: .env, .env.local, .env.<profile> and
+// .env.<profile>.local, each overriding the previous one. Missing layers are
+// skipped. Typically the profile comes from something like APP_ENV:
+//
+//	dotenv.Load(dotenv.WithProfile(os.Getenv("APP_ENV")))
+//
+// An empty profile disables layering.
+func WithProfile(profile string) Option {
+	return func(o *Options) {
+		o.Profile = profile
+	}
+}
+
+// WithSearchUp makes directory paths search their parent directories for the
+// nearest dotenv file, stopping at a directory containing .git or at the
+// root. Without WithFs the search starts at the working directory and may
+// leave it; the directory where the file is found becomes the root for
+// resolving paths.
+func WithSearchUp() Option {
+	return func(o *Options) {
+		o.SearchUp = true
+	}
+}
+
 // WithFs sets the filesystem root used to open paths. When not provided, the
 // current directory is used via os.OpenRoot(".").FS()
 func WithFs(rootFs fs.FS) Option {
@@ -42,14 +154,229 @@ func WithFs(rootFs fs.FS) Option {
 	}
 }
 
+// WithExpand enables expansion of $VAR and ${VAR} references inside values.
+// References resolve against keys parsed earlier (including from previous
+// files) and then against the process environment. Single-quoted values are
+// never expanded. The POSIX forms ${VAR:-default}, ${VAR:?message} and
+// ${VAR:+alternative} are supported; a failing ${VAR:?} is a *ParseError.
+//
+// A reference to a key that is only defined further down (or in a later
+// file) resolves to that definition, so order within a file rarely matters.
+// References that form a cycle fail with a *ParseError naming the cycle. A
+// key referencing itself, as in PATH=/bin:$PATH, sees its previous value.
+func WithExpand(expand bool) Option {
+	return func(o *Options) {
+		o.Expand = expand
+	}
+}
+
+// WithEscapes enables interpretation of \n, \t, \r, \" and \\ inside
+// double-quoted values. Single-quoted and unquoted values stay literal.
+func WithEscapes(escapes bool) Option {
+	return func(o *Options) {
+		o.Escapes = escapes
+	}
+}
+
+// WithExpandDepth limits how deeply references may chain during expansion,
+// 32 by default. Exceeding the limit is a *ParseError.
+func WithExpandDepth(depth int) Option {
+	return func(o *Options) {
+		o.ExpandDepth = depth
+	}
+}
+
+// WithMaxLineLength limits the length of a single line in bytes, 1 MiB by
+// default. Longer lines fail with a *ParseError instead of being read into
+// memory; n <= 0 removes the limit.
+func WithMaxLineLength(n int) Option {
+	return func(o *Options) {
+		o.MaxLineLength = n
+	}
+}
+
+// WithMaxFileSize limits the size of each file read, in bytes. Reading stops
+// with an error wrapping ErrLimitExceeded once a file grows past the limit,
+// so a path pointing at a large binary fails fast. Unlimited by default.
+func WithMaxFileSize(n int64) Option {
+	return func(o *Options) {
+		o.MaxFileSize = n
+	}
+}
+
+// WithMaxKeys limits the number of distinct keys across all files. Exceeding
+// it is an error wrapping ErrLimitExceeded. Unlimited by default.
+func WithMaxKeys(n int) Option {
+	return func(o *Options) {
+		o.MaxKeys = n
+	}
+}
+
+// ErrLimitExceeded is wrapped by errors caused by WithMaxFileSize and
+// WithMaxKeys.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// WithCommandSubstitution enables $(command) substitution inside expanded
+// values, executed by runner. It only takes effect together with
+// WithExpand(true) and is off by default, since it runs arbitrary commands
+// found in dotenv files. See ShellRunner for a ready-made runner.
+func WithCommandSubstitution(runner CommandRunner) Option {
+	return func(o *Options) {
+		o.CommandRunner = runner
+	}
+}
+
+// EmptyValues controls what an assignment without a value, like "KEY=",
+// does. Explicitly quoted empty values such as KEY="" always set an empty
+// string.
+type EmptyValues int
+
+const (
+	// EmptySet sets the variable to an empty string.
+	EmptySet EmptyValues = iota
+	// EmptySkip ignores the assignment; an earlier value is kept.
+	EmptySkip
+	// EmptyUnset removes the variable: Parse leaves the key out and Load
+	// calls os.Unsetenv, or the function given to WithUnsetter when
+	// WithSetter is used.
+	EmptyUnset
+)
+
+// WithEmptyValues sets how "KEY=" assignments are handled, EmptySet by
+// default.
+func WithEmptyValues(policy EmptyValues) Option {
+	return func(o *Options) {
+		o.EmptyValues = policy
+	}
+}
+
+// DuplicatePolicy controls what happens when a key is assigned more than
+// once in the same file. Keys repeated across files are not duplicates: later
+// files override earlier ones regardless of the policy.
+type DuplicatePolicy int
+
+const (
+	// DuplicateLast lets the last assignment win silently.
+	DuplicateLast DuplicatePolicy = iota
+	// DuplicateFirst keeps the first assignment and ignores the rest.
+	DuplicateFirst
+	// DuplicateError fails with a ParseError at the repeated assignment.
+	DuplicateError
+	// DuplicateWarn logs a warning and lets the last assignment win.
+	DuplicateWarn
+)
+
+// WithDuplicatePolicy sets how keys repeated within a single file are
+// handled, DuplicateLast by default.
+func WithDuplicatePolicy(policy DuplicatePolicy) Option {
+	return func(o *Options) {
+		o.Duplicates = policy
+	}
+}
+
+// WithNoOverride makes Load keep variables that are already present in the
+// process environment instead of replacing them with values from files.
+func WithNoOverride() Option {
+	return func(o *Options) {
+		o.NoOverride = true
+	}
+}
+
+// WithStrict makes parsing fail with a *ParseError on lines that are neither
+// blank, comments, nor valid KEY=VALUE pairs instead of skipping them.
+// Parsing continues past such lines so that every problem, including
+// duplicate keys and validation failures, is reported in one error built with
+// errors.Join; use errors.As to get at the individual errors.
+func WithStrict() Option {
+	return func(o *Options) {
+		o.Strict = true
+	}
+}
+
+// WithRequiredPaths makes a missing path (or a directory without a dotenv
+// file) fail loading instead of being logged and skipped.
+func WithRequiredPaths() Option {
+	return func(o *Options) {
+		o.RequirePaths = true
+	}
+}
+
+// WithSetter sets the function Load uses to export values, os.Setenv by
+// default. It lets values go into a map, a config struct or a test harness
+// instead of the process environment. WithNoOverride still consults the
+// process environment.
+func WithSetter(setter func(key, value string) error) Option {
+	return func(o *Options) {
+		o.Setter = setter
+		o.customSetter = true
+	}
+}
+
+// WithUnsetter sets the function Load uses to remove variables under
+// EmptyUnset. It pairs with WithSetter; without it, loading a key that
+// EmptyUnset removes fails rather than touching the process environment.
+func WithUnsetter(unsetter func(key string) error) Option {
+	return func(o *Options) {
+		o.Unsetter = unsetter
+	}
+}
+
+// unsetter returns the function removing variables from the setter's
+// target, or nil if there is none.
+func (o Options) unsetter() func(key string) error {
+	if o.Unsetter != nil || o.customSetter {
+		return o.Unsetter
+	}
+	return os.Unsetenv
+}
+
+// WithRequired lists keys that must be defined once all paths are parsed,
+// either by a file or by the process environment. Missing keys are reported
+// together in a single *MissingKeysError. Repeated use accumulates keys.
+func WithRequired(keys ...string) Option {
+	return func(o *Options) {
+		o.Required = append(o.Required, keys...)
+	}
+}
+
 // Logger is a minimal logger used by Load for informational and warning
 // messages. Bring your own implementation; a no-op logger is used by default.
+// Messages name keys, files and lines but never include values, unless
+// WithUnsafeValueLogging is used.
+//
+// Loggers that also implement DebugLogger receive debug messages tracing
+// every source read and every key overridden by a later definition.
 type Logger interface {
 	Info(msg string, args ...any)
 	Warn(msg string, args ...any)
 }
 
-var _ Logger = &slog.Logger{}
+// DebugLogger is implemented by loggers that accept debug messages, like
+// *slog.Logger.
+type DebugLogger interface {
+	Debug(msg string, args ...any)
+}
+
+var (
+	_ Logger      = &slog.Logger{}
+	_ DebugLogger = &slog.Logger{}
+)
+
+// WithSlog logs to l, including debug messages when l is enabled for
+// slog.LevelDebug. A nil l means slog.Default().
+func WithSlog(l *slog.Logger) Option {
+	if l == nil {
+		l = slog.Default()
+	}
+	return WithLogger(l)
+}
+
+// debug logs a debug message if the logger supports them.
+func (o Options) debug(msg string, args ...any) {
+	if l, ok := o.Logger.(DebugLogger); ok {
+		l.Debug(msg, args...)
+	}
+}
 
 // WithLogger sets a custom logger implementation used during loading.
 func WithLogger(l Logger) Option {
@@ -62,115 +389,410 @@ func validateOptions(opts Options) error {
 	if len(opts.Paths) == 0 {
 		return fmt.Errorf("should provide at least a single path")
 	}
+	if len(opts.Filenames) == 0 {
+		return fmt.Errorf("should provide at least a single file name")
+	}
 	if opts.RootFs == nil {
 		return fmt.Errorf("should provide root fs")
 	}
 	if opts.Logger == nil {
 		return fmt.Errorf("logger should be provided")
 	}
+	if opts.Setter == nil {
+		return fmt.Errorf("setter should be provided")
+	}
 	return nil
 }
 
 // Load reads .env entries from the configured paths and exports them via
-// os.Setenv. It is safe to call multiple times; later files override
-// earlier ones according to the provided paths.
-// Not found paths will be ignored and logged.
+// os.Setenv (see WithSetter). It is safe to call multiple times; later files
+// override earlier ones according to the provided paths.
+// Not found paths will be ignored and logged unless WithRequiredPaths is used.
+// Use WithNoOverride to keep variables that are already set.
+//
+// Load is safe for concurrent use. All paths and sources are parsed and
+// validated before the first variable is exported, and concurrent calls
+// export their values one at a time, so a call's values are never
+// interleaved with another's.
 func Load(userOptions ...Option) error {
+	_, err := LoadReport(userOptions...)
+	return err
+}
+
+// Parse reads .env entries from the configured paths and returns them as a
+// map without touching the process environment. Paths are resolved and
+// merged exactly like Load does.
+func Parse(userOptions ...Option) (map[string]string, error) {
+	opts, err := buildOptions(userOptions)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse .env file with these options: %w", err)
+	}
+
+	env, err := parse(context.Background(), opts)
+	if err != nil {
+		return nil, err
+	}
+	return env.values(), nil
+}
+
+func applyOptions(userOptions []Option) Options {
 	opts := Options{
-		Paths:  []string{"."},
-		Logger: nopLogger{},
+		Paths:     []string{"."},
+		Filenames: []string{".env"},
+		Logger:    nopLogger{},
+		Setter:    os.Setenv,
+
+		ExpandDepth:   32,
+		MaxLineLength: 1 << 20,
+
+		PermissionMask: DefaultPermissionMask,
 	}
 	for _, userOption := range userOptions {
 		userOption(&opts)
 	}
+	return opts
+}
+
+func buildOptions(userOptions []Option) (Options, error) {
+	opts := applyOptions(userOptions)
 
 	if opts.RootFs == nil {
-		root, err := os.OpenRoot(".")
+		rootDir := "."
+		if opts.SearchUp {
+			dir, found, err := searchUpOS(opts.Filenames)
+			if err != nil {
+				return opts, err
+			}
+			if found {
+				rootDir = dir
+			}
+		}
+		root, err := os.OpenRoot(rootDir)
 		if err != nil {
-			return fmt.Errorf("failed to create fs.FS from current directory: %w", err)
+			return opts, fmt.Errorf("failed to create fs.FS from current directory: %w", err)
 		}
 		opts.RootFs = root.FS()
 	}
 
 	if err := validateOptions(opts); err != nil {
-		return fmt.Errorf("can export .env file with these options: %w", err)
+		return opts, err
 	}
 
-	return load(opts)
+	return opts, nil
+}
+
+// entry is a parsed value together with the files it came from.
+type entry struct {
+	value string
+	file  string
+	line  int
+	// shadowed lists earlier files whose value for the key was overridden.
+	shadowed []string
+	// unset marks an empty assignment under EmptyUnset.
+	unset bool
 }
 
-func load(opts Options) error {
+type entries map[string]entry
+
+// values returns the parsed values, leaving out keys that are to be unset.
+func (e entries) values() map[string]string {
+	values := make(map[string]string, len(e))
+	for key, ent := range e {
+		if !ent.unset {
+			values[key] = ent.value
+		}
+	}
+	return values
+}
+
+// lookup returns the entry for key unless it is to be unset.
+func (e entries) lookup(key string) (entry, bool) {
+	ent, ok := e[key]
+	if !ok || ent.unset {
+		return entry{}, false
+	}
+	return ent, true
+}
+
+// parse reads, resolves and validates the configured files. In strict mode
+// it keeps going after a problem and reports all of them at once. ctx is
+// checked before every file system access and passed to providers.
+func parse(ctx context.Context, opts Options) (entries, error) {
+	start := time.Now()
+	ctx, span := opts.startSpan(ctx, "dotenv.parse")
+	env, err := parseSources(ctx, opts)
+	span.SetAttributes("keys", len(env))
+	span.End(err)
+	if opts.Metrics != nil {
+		if n := countParseErrors(err); n > 0 {
+			opts.Metrics.ParseErrors(n)
+		}
+		opts.Metrics.LoadDuration(time.Since(start), err)
+	}
+	return env, err
+}
+
+func parseSources(ctx context.Context, opts Options) (entries, error) {
+	var (
+		raws     []rawEntry
+		problems []error
+	)
+
+	var lock *lockVerifier
+	if opts.VerifyLock {
+		var err error
+		if lock, err = readLock(opts.RootFs); err != nil {
+			return nil, err
+		}
+		opts.lock = lock
+	}
+
+	// Sources are listed in declared order and read as they are listed, so
+	// that with WithConcurrency resolving the next path overlaps with reading
+	// the previous ones. A path that cannot be resolved ends the list; its
+	// error is returned once the sources before it are merged.
+	reader := newSourceReader(ctx, opts)
+	var pathErr error
 	for _, p := range opts.Paths {
-		info, err := fs.Stat(opts.RootFs, p)
+		if reader.stopped() {
+			break
+		}
+		if provider, ok, err := providerFor(p); ok {
+			if err != nil {
+				pathErr = err
+				break
+			}
+			name := redactURL(p)
+			reader.add(&source{name: name, read: func(ctx context.Context, raws *[]rawEntry) error {
+				return fetchProvider(ctx, opts, name, provider, raws)
+			}})
+			continue
+		}
+
+		envPaths, err := retry(ctx, opts, p, func(ctx context.Context) ([]string, error) {
+			envPaths, err := resolvePath(ctx, opts, p)
+			if err != nil {
+				return nil, err
+			}
+			return vaultPaths(ctx, opts, envPaths)
+		})
 		if err != nil {
-			if errors.Is(err, fs.ErrNotExist) {
-				opts.Logger.Warn("path not found", "path", p)
-				continue
+			pathErr = err
+			break
+		}
+		if len(envPaths) == 0 {
+			reader.add(&source{name: p, missing: true})
+		}
+		for _, envPath := range envPaths {
+			reader.add(&source{name: envPath, collect: true, read: func(ctx context.Context, raws *[]rawEntry) error {
+				return processFile(ctx, opts.RootFs, envPath, func(f fs.File) error {
+					if err := checkPermissions(opts, f, envPath); err != nil {
+						return err
+					}
+					r, verify := lock.reader(f, envPath)
+					if err := parseFile(ctx, opts, r, envPath, raws); err != nil {
+						return err
+					}
+					return verify()
+				})
+			}})
+		}
+	}
+	numPaths := len(reader.srcs)
+	if pathErr == nil {
+		for _, dir := range opts.KeyDirs {
+			reader.add(&source{name: dir, read: func(ctx context.Context, raws *[]rawEntry) error {
+				return parseKeyDir(ctx, opts, dir, raws)
+			}})
+		}
+		for _, provider := range opts.Providers {
+			name := providerName(provider)
+			reader.add(&source{name: name, read: func(ctx context.Context, raws *[]rawEntry) error {
+				return fetchProvider(ctx, opts, name, provider, raws)
+			}})
+		}
+	}
+	srcs := reader.wait()
+
+	// pathsDone runs once the sources of all paths are merged.
+	pathsDone := func() error {
+		if pathErr != nil {
+			return pathErr
+		}
+		if err := lock.missing(); err != nil {
+			if !opts.Strict {
+				return err
+			}
+			problems = append(problems, err)
+		}
+		return nil
+	}
+	for i, src := range srcs {
+		if i == numPaths {
+			if err := pathsDone(); err != nil {
+				return nil, err
+			}
+		}
+		if src.missing {
+			if opts.fileReports != nil {
+				*opts.fileReports = append(*opts.fileReports, FileReport{Path: src.name, Status: FileMissing})
 			}
-			return fmt.Errorf("stat %s: %w", p, err)
+			continue
 		}
+		if src.skipped {
+			continue
+		}
+
+		raws = append(raws, src.raws...)
+		opts.debug("source read", "source", src.name, "keys", len(src.raws), "duration", src.duration)
+		if src.err == nil && opts.Metrics != nil {
+			opts.Metrics.FileLoaded(src.name, len(src.raws))
+		}
+		opts.reportFile(src.name, len(src.raws), src.duration, src.err)
+		if src.err != nil {
+			if !src.collect || !opts.Strict || ctx.Err() != nil {
+				return nil, src.err
+			}
+			problems = append(problems, src.err)
+		}
+	}
+	if numPaths == len(srcs) {
+		if err := pathsDone(); err != nil {
+			return nil, err
+		}
+	}
 
-		var envPath string
+	env, err := resolveEntries(ctx, opts, raws)
+	if err != nil {
+		return nil, joinErrors(append(problems, err))
+	}
+	if err := transformValues(opts, env); err != nil {
+		if !opts.Strict {
+			return nil, err
+		}
+		problems = append(problems, err)
+	}
+	env = selectKeys(opts, env)
+	if err := applyValueHooks(opts, env); err != nil {
+		if !opts.Strict {
+			return nil, err
+		}
+		problems = append(problems, err)
+	}
+
+	if err := checkRequired(opts.Required, env); err != nil {
+		if !opts.Strict {
+			return nil, err
+		}
+		problems = append(problems, err)
+	}
+	if err := applySchema(opts, env); err != nil {
+		if !opts.Strict {
+			return nil, err
+		}
+		problems = append(problems, err)
+	}
+	if err := joinErrors(problems); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// resolvePath maps a configured path to the dotenv files that should be read,
+// in order. Directories are searched for the configured file names; with a
+// profile set, the profile layers of the resulting file are returned instead.
+func resolvePath(ctx context.Context, opts Options, p string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	info, err := fs.Stat(opts.RootFs, p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) && !opts.RequirePaths {
+			opts.Logger.Warn("path not found", "path", p)
+			return nil, nil
+		}
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, &PathError{Path: p}
+		}
+		return nil, fmt.Errorf("stat %s: %w", p, err)
+	}
+
+	if opts.SearchUp && info.IsDir() {
+		dir, found, err := searchUpFS(opts.RootFs, p, opts.Filenames)
+		if err != nil {
+			return nil, err
+		}
+		if found && dir != p {
+			opts.Logger.Info("dotenv found in parent directory", "path", p, "dir", dir)
+			p = dir
+		}
+	}
+
+	if opts.Profile != "" {
+		base := p
 		if info.IsDir() {
-			envPath = path.Join(p, ".env")
-			opts.Logger.Info("directory detected; joining dotenv", "path", p, "dotenv", envPath)
-		} else {
-			envPath = p
+			base = path.Join(p, opts.Filenames[0])
 		}
+		return profileLayers(opts, base)
+	}
 
+	if !info.IsDir() {
+		return []string{p}, nil
+	}
+
+	for _, name := range opts.Filenames {
+		envPath := path.Join(p, name)
 		if _, err := fs.Stat(opts.RootFs, envPath); err != nil {
 			if errors.Is(err, fs.ErrNotExist) {
-				opts.Logger.Warn("dotenv not found", "path", envPath)
 				continue
 			}
-			return fmt.Errorf("stat %s: %w", envPath, err)
-		}
-
-		err = processFile(opts.RootFs, envPath, func(f fs.File) error {
-			scanner := bufio.NewScanner(f)
-			for scanner.Scan() {
-				line := strings.TrimSpace(scanner.Text())
-				if line == "" || strings.HasPrefix(line, "#") {
-					continue
-				}
-				eq := strings.IndexByte(line, '=')
-				if eq <= 0 {
-					continue
-				}
-				key := strings.TrimSpace(line[:eq])
-				val := strings.TrimSpace(line[eq+1:])
-
-				if len(val) >= 2 {
-					if (val[0] == '"' && val[len(val)-1] == '"') || (val[0] == '\'' && val[len(val)-1] == '\'') {
-						val = val[1 : len(val)-1]
-					}
-				}
+			return nil, fmt.Errorf("stat %s: %w", envPath, err)
+		}
+		opts.Logger.Info("directory detected; joining dotenv", "path", p, "dotenv", envPath)
+		return []string{envPath}, nil
+	}
 
-				if key != "" {
-					if err := os.Setenv(key, val); err != nil {
-						return fmt.Errorf("setenv %s: %w", key, err)
-					}
-				}
-			}
-			if err := scanner.Err(); err != nil {
-				return fmt.Errorf("read %s: %w", envPath, err)
+	if opts.RequirePaths {
+		return nil, &PathError{Path: p, Filenames: opts.Filenames}
+	}
+	opts.Logger.Warn("dotenv not found", "path", p, "filenames", opts.Filenames)
+	return nil, nil
+}
+
+// profileLayers returns the existing files of the profile cascade for base:
+// base, base.local, base.<profile> and base.<profile>.local.
+func profileLayers(opts Options, base string) ([]string, error) {
+	layers := []string{
+		base,
+		base + ".local",
+		base + "." + opts.Profile,
+		base + "." + opts.Profile + ".local",
+	}
+
+	var existing []string
+	for _, layer := range layers {
+		if _, err := fs.Stat(opts.RootFs, layer); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				opts.Logger.Info("profile layer not found; skipping", "path", layer)
+				continue
 			}
-			return nil
-		})
-		if err != nil {
-			return err
+			return nil, fmt.Errorf("stat %s: %w", layer, err)
 		}
+		existing = append(existing, layer)
 	}
-	return nil
+	return existing, nil
 }
 
-func processFile(rootFs fs.FS, path string, processorFn func(f fs.File) error) error {
+func processFile(ctx context.Context, rootFs fs.FS, path string, processorFn func(f fs.File) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	f, err := rootFs.Open(path)
 	if err != nil {
 		return fmt.Errorf("open %q: %w", path, err)
 	}
 
-	err = processorFn(f)
+	err = processorFn(contextFile{File: f, ctx: ctx})
 	closeErr := f.Close()
 	if err != nil {
 		if closeErr != nil {
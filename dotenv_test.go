@@ -2,7 +2,9 @@ package dotenv
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	iofs "io/fs"
 	"os"
 	"strings"
 	"testing"
@@ -31,6 +33,56 @@ BAZ=qux
 		assertNoError(t, err)
 	})
 
+	t.Run("required paths fail when missing", func(t *testing.T) {
+		fs := fstest.MapFS{
+			"empty/README": &fstest.MapFile{Data: []byte("no dotenv here")},
+		}
+		for _, p := range []string{"missing", "empty"} {
+			err := Load(WithPaths(p), WithFs(fs), WithRequiredPaths())
+			if !errors.Is(err, iofs.ErrNotExist) {
+				t.Fatalf("path %s: expected not exist error, got %v", p, err)
+			}
+		}
+	})
+
+	t.Run("custom file names are tried in order", func(t *testing.T) {
+		fs := fstest.MapFS{
+			"a/.env.local": &fstest.MapFile{Data: []byte("NAME=local\n")},
+			"a/.env":       &fstest.MapFile{Data: []byte("NAME=default\n")},
+			"b/.env":       &fstest.MapFile{Data: []byte("NAME=fallback\n")},
+		}
+
+		env, err := Parse(WithPaths("a"), WithFs(fs), WithFilename(".env.local", ".env"))
+		assertNoError(t, err)
+		assertEqual(t, env["NAME"], "local")
+
+		env, err = Parse(WithPaths("b"), WithFs(fs), WithFilename(".env.local", ".env"))
+		assertNoError(t, err)
+		assertEqual(t, env["NAME"], "fallback")
+	})
+
+	t.Run("profile layers override in order", func(t *testing.T) {
+		fs := fstest.MapFS{
+			"app/.env":                   &fstest.MapFile{Data: []byte("A=base\nB=base\nC=base\nD=base\n")},
+			"app/.env.local":             &fstest.MapFile{Data: []byte("B=local\nC=local\nD=local\n")},
+			"app/.env.development":       &fstest.MapFile{Data: []byte("C=development\nD=development\n")},
+			"app/.env.development.local": &fstest.MapFile{Data: []byte("D=development.local\n")},
+			"app/.env.production":        &fstest.MapFile{Data: []byte("A=production\n")},
+		}
+
+		env, err := Parse(WithPaths("app"), WithFs(fs), WithProfile("development"))
+		assertNoError(t, err)
+		assertEqual(t, env["A"], "base")
+		assertEqual(t, env["B"], "local")
+		assertEqual(t, env["C"], "development")
+		assertEqual(t, env["D"], "development.local")
+
+		env, err = Parse(WithPaths("app"), WithFs(fs), WithProfile("production"))
+		assertNoError(t, err)
+		assertEqual(t, env["A"], "production")
+		assertEqual(t, env["D"], "local")
+	})
+
 	t.Run("later paths override earlier", func(t *testing.T) {
 		fs := fstest.MapFS{
 			"a/.env": &fstest.MapFile{Data: []byte(`KEY=1
@@ -75,6 +127,46 @@ TITLE=' Sr Dev '
 		assertEqual(t, os.Getenv("X"), "1")
 	})
 
+	t.Run("no override keeps existing variables", func(t *testing.T) {
+		fs := fstest.MapFS{
+			".env": &fstest.MapFile{Data: []byte(`PRESET=file
+FRESH=file
+`)},
+		}
+		t.Setenv("PRESET", "ci")
+		os.Unsetenv("FRESH")
+
+		err := Load(WithFs(fs), WithNoOverride())
+		assertNoError(t, err)
+		assertEqual(t, os.Getenv("PRESET"), "ci")
+		assertEqual(t, os.Getenv("FRESH"), "file")
+	})
+
+	t.Run("custom setter receives values", func(t *testing.T) {
+		fs := fstest.MapFS{
+			".env": &fstest.MapFile{Data: []byte("SETTER_A=1\nSETTER_B=2\n")},
+		}
+		os.Unsetenv("SETTER_A")
+
+		got := map[string]string{}
+		err := Load(WithFs(fs), WithSetter(func(key, value string) error {
+			got[key] = value
+			return nil
+		}))
+		assertNoError(t, err)
+		assertEqual(t, len(got), 2)
+		assertEqual(t, got["SETTER_B"], "2")
+		_, set := os.LookupEnv("SETTER_A")
+		assertEqual(t, set, false)
+
+		err = Load(WithFs(fs), WithSetter(func(key, value string) error {
+			return errors.New("boom")
+		}))
+		if err == nil || !strings.Contains(err.Error(), "boom") {
+			t.Fatalf("expected setter error, got %v", err)
+		}
+	})
+
 	t.Run("logger reports joins and not-found", func(t *testing.T) {
 		fs := fstest.MapFS{
 			// Only second directory has dotenv
@@ -95,6 +187,104 @@ TITLE=' Sr Dev '
 	})
 }
 
+func Test_parse(t *testing.T) {
+	t.Run("returns merged values without touching env", func(t *testing.T) {
+		fs := fstest.MapFS{
+			"a/.env": &fstest.MapFile{Data: []byte(`KEY=1
+ONLY_A=a
+`)},
+			"b/.env": &fstest.MapFile{Data: []byte(`KEY=2
+`)},
+		}
+		os.Unsetenv("KEY")
+		os.Unsetenv("ONLY_A")
+
+		env, err := Parse(WithPaths("a", "b"), WithFs(fs))
+		assertNoError(t, err)
+		assertEqual(t, len(env), 2)
+		assertEqual(t, env["KEY"], "2")
+		assertEqual(t, env["ONLY_A"], "a")
+
+		_, set := os.LookupEnv("KEY")
+		assertEqual(t, set, false)
+	})
+}
+
+func Test_expand(t *testing.T) {
+	t.Run("expands references from file and environment", func(t *testing.T) {
+		fs := fstest.MapFS{
+			"a/.env": &fstest.MapFile{Data: []byte(`HOST=localhost
+`)},
+			"b/.env": &fstest.MapFile{Data: []byte(`PORT=8080
+URL=http://$HOST:${PORT}/$DOTENV_TEST_PATH
+RAW='$HOST'
+PRICE=\$5
+`)},
+		}
+		t.Setenv("DOTENV_TEST_PATH", "api")
+
+		env, err := Parse(WithPaths("a", "b"), WithFs(fs), WithExpand(true))
+		assertNoError(t, err)
+		assertEqual(t, env["URL"], "http://localhost:8080/api")
+		assertEqual(t, env["RAW"], "$HOST")
+		assertEqual(t, env["PRICE"], "$5")
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		fs := fstest.MapFS{
+			".env": &fstest.MapFile{Data: []byte(`A=1
+B=$A
+`)},
+		}
+		env, err := Parse(WithFs(fs))
+		assertNoError(t, err)
+		assertEqual(t, env["B"], "$A")
+	})
+
+	t.Run("resolves forward references", func(t *testing.T) {
+		fs := fstest.MapFS{
+			".env": &fstest.MapFile{Data: []byte(`B=${A}x
+A=1
+C=${A}x
+A=2
+D=${A}x
+`)},
+		}
+		os.Unsetenv("A")
+		env, err := Parse(WithFs(fs), WithExpand(true))
+		assertNoError(t, err)
+		assertEqual(t, env["B"], "1x")
+		assertEqual(t, env["C"], "1x")
+		assertEqual(t, env["D"], "2x")
+	})
+
+	t.Run("self references see previous value", func(t *testing.T) {
+		t.Setenv("DOTENV_TEST_LIST", "env")
+		env, err := ParseString("DOTENV_TEST_LIST=a:$DOTENV_TEST_LIST\nDOTENV_TEST_LIST=b:$DOTENV_TEST_LIST\n", WithExpand(true))
+		assertNoError(t, err)
+		assertEqual(t, env["DOTENV_TEST_LIST"], "b:a:env")
+	})
+
+	t.Run("detects cycles", func(t *testing.T) {
+		_, err := ParseString("X=1\nA=${B}\nB=x$C\nC=${A}\n", WithExpand(true))
+		if err == nil || err.Error() != "<string>:2:3: expansion cycle: A -> B -> C -> A" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("limits depth", func(t *testing.T) {
+		content := "A=$B\nB=$C\nC=$D\nD=end\n"
+		env, err := ParseString(content, WithExpand(true), WithExpandDepth(4))
+		assertNoError(t, err)
+		assertEqual(t, env["A"], "end")
+
+		_, err = ParseString(content, WithExpand(true), WithExpandDepth(2))
+		if err == nil || err.Error() != "<string>:3:3: expansion depth limit 2 exceeded" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
 type testLogger struct{ bytes.Buffer }
 
 func (l *testLogger) log(msg string, args ...any) {
@@ -0,0 +1,226 @@
+// Package dotenvlint checks how dotenv files are written, for editors, CI
+// bots and the dotenv check command:
+//
+//	doc, err := dotenv.ParseDocument(f)
+//	...
+//	for _, f := range dotenvlint.Check(doc) {
+//		fmt.Printf("%s:%d:%d: %s (%s)\n", name, f.Line, f.Col, f.Message, f.Rule)
+//	}
+//
+// Most findings carry a Fix; ApplyFixes rewrites a document with them.
+package dotenvlint
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pechorka/dotenv"
+)
+
+// Rule IDs of the findings.
+const (
+	// RuleSyntax flags lines that strict parsing rejects.
+	RuleSyntax = "syntax"
+	// RuleDuplicate flags keys defined more than once.
+	RuleDuplicate = "duplicate"
+	// RuleSpacing flags whitespace around '='.
+	RuleSpacing = "spacing"
+	// RuleUnquoted flags unquoted values containing whitespace.
+	RuleUnquoted = "unquoted"
+	// RuleUppercase flags keys that are not upper case.
+	RuleUppercase = "uppercase"
+)
+
+// Severity tells how serious a finding is.
+type Severity int
+
+const (
+	// SeverityWarning marks style problems; the file loads as intended.
+	SeverityWarning Severity = iota
+	// SeverityError marks problems that make loading fail or lose values.
+	SeverityError
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// Finding is a problem found in a document.
+type Finding struct {
+	// Line and Col are 1-based.
+	Line     int
+	Col      int
+	Rule     string
+	Severity Severity
+	Message  string
+	// Fix suggests how to resolve the finding, or is nil.
+	Fix *Fix
+}
+
+// Fix is a suggested edit of the statement starting at Line: it is
+// replaced with Text, or removed when Delete is set.
+type Fix struct {
+	Line   int
+	Text   string
+	Delete bool
+}
+
+// Check applies every rule to doc and returns the findings sorted by
+// position.
+func Check(doc *dotenv.Document) []Finding {
+	var findings []Finding
+	lastLine := map[string]int{}
+	firstLine := map[string]int{}
+	for _, e := range doc.Entries() {
+		if e.Key == "" {
+			findings = append(findings, syntax(e)...)
+			continue
+		}
+
+		keyCol := keyOffset(e.Raw) + 1
+		if last, ok := lastLine[e.Key]; ok {
+			findings = append(findings, Finding{
+				Line: e.Line, Col: keyCol, Rule: RuleDuplicate, Severity: SeverityWarning,
+				Message: fmt.Sprintf("duplicate key %s, first defined on line %d", e.Key, firstLine[e.Key]),
+				Fix:     &Fix{Line: last, Delete: true},
+			})
+		} else {
+			firstLine[e.Key] = e.Line
+		}
+		lastLine[e.Key] = e.Line
+
+		if upper := strings.ToUpper(e.Key); e.Key != upper {
+			text := e.Raw[:keyCol-1] + upper + e.Raw[keyCol-1+len(e.RawKey):]
+			findings = append(findings, Finding{
+				Line: e.Line, Col: keyCol, Rule: RuleUppercase, Severity: SeverityWarning,
+				Message: fmt.Sprintf("key %s is not upper case", e.Key),
+				Fix:     &Fix{Line: e.Line, Text: text},
+			})
+		}
+
+		findings = append(findings, spacing(e, keyCol)...)
+
+		// Keys inherited from the environment have no value to quote.
+		if e.Quote == 0 && e.ValueCol > 0 && strings.ContainsAny(e.Value, " \t") {
+			f := Finding{
+				Line: e.Line, Col: e.ValueCol, Rule: RuleUnquoted, Severity: SeverityWarning,
+				Message: fmt.Sprintf("value of %s contains spaces but is not quoted", e.Key),
+			}
+			if quoted, ok := quote(e.Raw[e.ValueCol-1 : len(e.Raw)-len(e.Comment)]); ok {
+				f.Fix = &Fix{Line: e.Line, Text: e.Raw[:e.ValueCol-1] + quoted + e.Comment}
+			}
+			findings = append(findings, f)
+		}
+	}
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Col, b.Col))
+	})
+	return findings
+}
+
+// ApplyFixes returns doc in dotenv format with the fixes of findings
+// applied. When several fixes target the same statement only the first is
+// applied; running Check again reports what is left.
+func ApplyFixes(doc *dotenv.Document, findings []Finding) []byte {
+	fixes := map[int]*Fix{}
+	for _, f := range findings {
+		if f.Fix != nil && fixes[f.Fix.Line] == nil {
+			fixes[f.Fix.Line] = f.Fix
+		}
+	}
+	var b strings.Builder
+	for _, e := range doc.Entries() {
+		text := e.Raw
+		if fix := fixes[e.Line]; fix != nil {
+			if fix.Delete {
+				continue
+			}
+			text = fix.Text
+		}
+		b.WriteString(text)
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
+
+// syntax reports why strict parsing rejects a line that is neither blank
+// nor a comment.
+func syntax(e dotenv.Entry) []Finding {
+	_, err := dotenv.ParseString(e.Raw, dotenv.WithStrict())
+	if err == nil {
+		return nil
+	}
+	var perr *dotenv.ParseError
+	if !errors.As(err, &perr) {
+		return nil
+	}
+	return []Finding{{
+		Line: e.Line + perr.Line - 1, Col: perr.Col, Rule: RuleSyntax, Severity: SeverityError,
+		Message: perr.Reason,
+	}}
+}
+
+// spacing reports whitespace before and after the '=' of e, whose key
+// starts at keyCol.
+func spacing(e dotenv.Entry, keyCol int) []Finding {
+	eq := keyCol - 1 + len(e.RawKey)
+	rest := e.Raw[eq:]
+	before := len(rest) - len(strings.TrimLeft(rest, " \t"))
+	eq += before
+	value, ok := strings.CutPrefix(e.Raw[eq:], "=")
+	if !ok {
+		return nil
+	}
+	after := len(value) - len(strings.TrimLeft(value, " \t"))
+	if e.Value == "" && e.Quote == 0 {
+		after = 0
+	}
+	if before == 0 && after == 0 {
+		return nil
+	}
+
+	fix := &Fix{Line: e.Line, Text: e.Raw[:keyCol-1] + e.RawKey + "=" + value[after:]}
+	var findings []Finding
+	if before > 0 {
+		findings = append(findings, Finding{
+			Line: e.Line, Col: eq - before + 1, Rule: RuleSpacing, Severity: SeverityWarning,
+			Message: "space before '='", Fix: fix,
+		})
+	}
+	if after > 0 {
+		findings = append(findings, Finding{
+			Line: e.Line, Col: eq + 2, Rule: RuleSpacing, Severity: SeverityWarning,
+			Message: "space after '='", Fix: fix,
+		})
+	}
+	return findings
+}
+
+// quote wraps an unquoted value in quotes that keep its meaning: double
+// quotes, or single quotes when the value holds characters double quotes
+// would interpret. It fails when neither fits.
+func quote(raw string) (string, bool) {
+	if !strings.ContainsAny(raw, `"\`) {
+		return `"` + raw + `"`, true
+	}
+	if !strings.ContainsAny(raw, `'$`) {
+		return "'" + raw + "'", true
+	}
+	return "", false
+}
+
+// keyOffset returns the offset of the key in raw, skipping indentation and
+// an export keyword.
+func keyOffset(raw string) int {
+	line := strings.TrimLeft(raw, " \t")
+	if rest, ok := strings.CutPrefix(line, "export"); ok && rest != strings.TrimLeft(rest, " \t") {
+		line = strings.TrimLeft(rest, " \t")
+	}
+	return len(raw) - len(line)
+}
@@ -0,0 +1,110 @@
+package dotenvlint_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pechorka/dotenv"
+	"github.com/pechorka/dotenv/dotenvlint"
+)
+
+func parse(t *testing.T, s string) *dotenv.Document {
+	t.Helper()
+	doc, err := dotenv.ParseDocument(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestCheck(t *testing.T) {
+	doc := parse(t, `# comment
+A=1
+BROKEN
+B = 2
+export lower=x
+PATHS=a b\c $HOME
+A=3
+`)
+	type want struct {
+		line, col int
+		rule      string
+		severity  dotenvlint.Severity
+		fix       *dotenvlint.Fix
+	}
+	wants := []want{
+		{3, 1, dotenvlint.RuleSyntax, dotenvlint.SeverityError, nil},
+		{4, 2, dotenvlint.RuleSpacing, dotenvlint.SeverityWarning, &dotenvlint.Fix{Line: 4, Text: "B=2"}},
+		{4, 4, dotenvlint.RuleSpacing, dotenvlint.SeverityWarning, &dotenvlint.Fix{Line: 4, Text: "B=2"}},
+		{5, 8, dotenvlint.RuleUppercase, dotenvlint.SeverityWarning, &dotenvlint.Fix{Line: 5, Text: "export LOWER=x"}},
+		{6, 7, dotenvlint.RuleUnquoted, dotenvlint.SeverityWarning, nil},
+		{7, 1, dotenvlint.RuleDuplicate, dotenvlint.SeverityWarning, &dotenvlint.Fix{Line: 2, Delete: true}},
+	}
+
+	findings := dotenvlint.Check(doc)
+	if len(findings) != len(wants) {
+		t.Fatalf("got %d findings, want %d: %+v", len(findings), len(wants), findings)
+	}
+	for i, f := range findings {
+		w := wants[i]
+		if f.Line != w.line || f.Col != w.col || f.Rule != w.rule || f.Severity != w.severity {
+			t.Errorf("finding %d = %d:%d %s %s, want %d:%d %s %s",
+				i, f.Line, f.Col, f.Rule, f.Severity, w.line, w.col, w.rule, w.severity)
+		}
+		if (f.Fix == nil) != (w.fix == nil) || f.Fix != nil && *f.Fix != *w.fix {
+			t.Errorf("finding %d fix = %+v, want %+v", i, f.Fix, w.fix)
+		}
+	}
+}
+
+func TestApplyFixes(t *testing.T) {
+	doc := parse(t, "A=1\nB = two words # note\nC=it's here\nD=say \"hi\" there\nA=2\n")
+	got := string(dotenvlint.ApplyFixes(doc, dotenvlint.Check(doc)))
+	want := "B=two words # note\nC=\"it's here\"\nD='say \"hi\" there'\nA=2\n"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	// The spacing fix came first on line 2; the quoting fix is left.
+	findings := dotenvlint.Check(parse(t, got))
+	if len(findings) != 1 || findings[0].Rule != dotenvlint.RuleUnquoted {
+		t.Fatalf("unexpected findings after fixing: %+v", findings)
+	}
+}
+
+func TestCheckOtherDialects(t *testing.T) {
+	t.Setenv("LINT_INHERITED", "two words")
+	docker, err := dotenv.ParseDocument(strings.NewReader("LINT_INHERITED\n"), dotenv.WithDialect(dotenv.DialectDocker))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findings := dotenvlint.Check(docker); len(findings) != 0 {
+		t.Fatalf("unexpected findings for an inherited key: %+v", findings)
+	}
+
+	ini, err := dotenv.ParseDocument(strings.NewReader("[db]\nhost = x\n"), dotenv.WithDialect(dotenv.DialectINI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spacing []dotenvlint.Finding
+	for _, f := range dotenvlint.Check(ini) {
+		if f.Line == 2 {
+			spacing = append(spacing, f)
+		}
+	}
+	if len(spacing) != 2 || spacing[0].Rule != dotenvlint.RuleSpacing || spacing[0].Col != 5 {
+		t.Fatalf("unexpected findings for the section key: %+v", spacing)
+	}
+	if fix := spacing[0].Fix; fix == nil || fix.Text != "host=x" {
+		t.Fatalf("unexpected fix: %+v", fix)
+	}
+}
+
+func TestSeverityString(t *testing.T) {
+	if s := dotenvlint.SeverityError.String(); s != "error" {
+		t.Fatalf("got %q", s)
+	}
+	if s := dotenvlint.SeverityWarning.String(); s != "warning" {
+		t.Fatalf("got %q", s)
+	}
+}
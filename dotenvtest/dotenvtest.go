@@ -0,0 +1,31 @@
+// Package dotenvtest loads dotenv files in tests. Values are exported with
+// t.Setenv, so the testing framework restores the environment when the test
+// ends and rejects use in parallel tests.
+package dotenvtest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pechorka/dotenv"
+)
+
+// Load works like dotenv.Load but exports values through t.Setenv. Loading
+// errors fail the test immediately.
+func Load(t testing.TB, userOptions ...dotenv.Option) {
+	t.Helper()
+
+	setter := dotenv.WithSetter(func(key, value string) error {
+		t.Setenv(key, value)
+		return nil
+	})
+	// t.Setenv records the previous value, so the cleanup puts back
+	// variables removed under dotenv.EmptyUnset too.
+	unsetter := dotenv.WithUnsetter(func(key string) error {
+		t.Setenv(key, "")
+		return os.Unsetenv(key)
+	})
+	if err := dotenv.Load(append(userOptions, setter, unsetter)...); err != nil {
+		t.Fatalf("dotenvtest: load: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package dotenvtest_test
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pechorka/dotenv"
+	"github.com/pechorka/dotenv/dotenvtest"
+)
+
+func TestLoad(t *testing.T) {
+	fs := fstest.MapFS{
+		".env": &fstest.MapFile{Data: []byte("DOTENVTEST_KEY=value\n")},
+	}
+	os.Unsetenv("DOTENVTEST_KEY")
+
+	t.Run("sets values for the test", func(t *testing.T) {
+		dotenvtest.Load(t, dotenv.WithFs(fs))
+		if got := os.Getenv("DOTENVTEST_KEY"); got != "value" {
+			t.Fatalf("got=%v, want=%v", got, "value")
+		}
+	})
+
+	if _, set := os.LookupEnv("DOTENVTEST_KEY"); set {
+		t.Fatal("expected DOTENVTEST_KEY to be unset after the subtest")
+	}
+}
+
+func TestLoadEmptyUnset(t *testing.T) {
+	fs := fstest.MapFS{
+		".env": &fstest.MapFile{Data: []byte("DOTENVTEST_GONE=\n")},
+	}
+	os.Setenv("DOTENVTEST_GONE", "kept")
+	defer os.Unsetenv("DOTENVTEST_GONE")
+
+	t.Run("unsets values for the test", func(t *testing.T) {
+		dotenvtest.Load(t, dotenv.WithFs(fs), dotenv.WithEmptyValues(dotenv.EmptyUnset))
+		if _, set := os.LookupEnv("DOTENVTEST_GONE"); set {
+			t.Fatal("expected DOTENVTEST_GONE to be unset in the subtest")
+		}
+	})
+
+	if got := os.Getenv("DOTENVTEST_GONE"); got != "kept" {
+		t.Fatalf("got=%v, want=%v", got, "kept")
+	}
+}
@@ -0,0 +1,85 @@
+package dotenv
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_emptyValues(t *testing.T) {
+	const content = "A=1\nA=\nB=\nC=\"\"\n"
+
+	t.Run("set by default", func(t *testing.T) {
+		env, err := ParseString(content)
+		assertNoError(t, err)
+		assertEqual(t, len(env), 3)
+		assertEqual(t, env["A"], "")
+		assertEqual(t, env["B"], "")
+	})
+
+	t.Run("skip keeps earlier value", func(t *testing.T) {
+		env, err := ParseString(content, WithEmptyValues(EmptySkip))
+		assertNoError(t, err)
+		assertEqual(t, len(env), 2)
+		assertEqual(t, env["A"], "1")
+		_, ok := env["B"]
+		assertEqual(t, ok, false)
+		assertEqual(t, env["C"], "")
+	})
+
+	t.Run("unset leaves keys out", func(t *testing.T) {
+		env, err := ParseString(content, WithEmptyValues(EmptyUnset))
+		assertNoError(t, err)
+		assertEqual(t, len(env), 1)
+		assertEqual(t, env["C"], "")
+	})
+
+	t.Run("unset removes variables on load", func(t *testing.T) {
+		fs := fstest.MapFS{".env": &fstest.MapFile{Data: []byte("EMPTY_GONE=\n")}}
+		t.Setenv("EMPTY_GONE", "ci")
+
+		report, err := LoadReport(WithFs(fs), WithEmptyValues(EmptyUnset))
+		assertNoError(t, err)
+		assertEqual(t, len(report.Unset), 1)
+		_, ok := os.LookupEnv("EMPTY_GONE")
+		assertEqual(t, ok, false)
+	})
+
+	t.Run("unset goes through the unsetter with a custom setter", func(t *testing.T) {
+		fs := fstest.MapFS{".env": &fstest.MapFile{Data: []byte("EMPTY_GONE=\n")}}
+		t.Setenv("EMPTY_GONE", "ci")
+		target := map[string]string{"EMPTY_GONE": "old"}
+		setter := WithSetter(func(key, value string) error {
+			target[key] = value
+			return nil
+		})
+		unsetter := WithUnsetter(func(key string) error {
+			delete(target, key)
+			return nil
+		})
+
+		err := Load(WithFs(fs), WithEmptyValues(EmptyUnset), setter, unsetter)
+		assertNoError(t, err)
+		assertEqual(t, len(target), 0)
+		assertEqual(t, os.Getenv("EMPTY_GONE"), "ci")
+
+		err = Load(WithFs(fs), WithEmptyValues(EmptyUnset), setter)
+		if err == nil {
+			t.Fatal("expected error for custom setter without unsetter")
+		}
+		assertEqual(t, os.Getenv("EMPTY_GONE"), "ci")
+	})
+
+	t.Run("unset drops key from environ", func(t *testing.T) {
+		fs := fstest.MapFS{".env": &fstest.MapFile{Data: []byte("EMPTY_GONE=\n")}}
+		t.Setenv("EMPTY_GONE", "ci")
+
+		env, err := Environ(WithFs(fs), WithEmptyValues(EmptyUnset))
+		assertNoError(t, err)
+		for _, kv := range env {
+			if environKey(kv) == "EMPTY_GONE" {
+				t.Fatalf("EMPTY_GONE still in environ: %q", kv)
+			}
+		}
+	})
+}
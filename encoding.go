@@ -0,0 +1,94 @@
+package dotenv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding selects how the bytes of a dotenv file are decoded before parsing.
+type Encoding int
+
+const (
+	// EncodingUTF8 reads files as UTF-8 without any detection. This is the
+	// default.
+	EncodingUTF8 Encoding = iota
+	// EncodingAuto looks for a byte order mark and decodes UTF-8, UTF-16LE
+	// and UTF-16BE accordingly. Files without a BOM that are not valid UTF-8
+	// are read as Latin-1.
+	EncodingAuto
+	// EncodingUTF16LE reads files as little-endian UTF-16.
+	EncodingUTF16LE
+	// EncodingUTF16BE reads files as big-endian UTF-16.
+	EncodingUTF16BE
+	// EncodingLatin1 reads files as ISO-8859-1.
+	EncodingLatin1
+)
+
+// WithEncoding sets how files are decoded, EncodingUTF8 by default. Use
+// EncodingAuto for files written by Windows tools, which often save UTF-16
+// with a byte order mark.
+func WithEncoding(enc Encoding) Option {
+	return func(o *Options) {
+		o.Encoding = enc
+	}
+}
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// decode reads r completely and returns its content transcoded to UTF-8.
+func decode(r io.Reader, enc Encoding) (io.Reader, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if enc == EncodingAuto {
+		switch {
+		case bytes.HasPrefix(data, bomUTF8):
+			return bytes.NewReader(data[len(bomUTF8):]), nil
+		case bytes.HasPrefix(data, bomUTF16LE):
+			enc = EncodingUTF16LE
+		case bytes.HasPrefix(data, bomUTF16BE):
+			enc = EncodingUTF16BE
+		case utf8.Valid(data):
+			return bytes.NewReader(data), nil
+		default:
+			enc = EncodingLatin1
+		}
+	}
+
+	switch enc {
+	case EncodingUTF16LE:
+		return decodeUTF16(bytes.TrimPrefix(data, bomUTF16LE), binary.LittleEndian)
+	case EncodingUTF16BE:
+		return decodeUTF16(bytes.TrimPrefix(data, bomUTF16BE), binary.BigEndian)
+	case EncodingLatin1:
+		var b strings.Builder
+		b.Grow(len(data))
+		for _, c := range data {
+			b.WriteRune(rune(c))
+		}
+		return strings.NewReader(b.String()), nil
+	}
+	return bytes.NewReader(data), nil
+}
+
+func decodeUTF16(data []byte, order binary.ByteOrder) (io.Reader, error) {
+	if len(data)%2 != 0 {
+		return nil, errors.New("invalid UTF-16: odd number of bytes")
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+	return strings.NewReader(string(utf16.Decode(units))), nil
+}
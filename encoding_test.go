@@ -0,0 +1,57 @@
+package dotenv
+
+import (
+	"testing"
+	"unicode/utf16"
+)
+
+func Test_encoding(t *testing.T) {
+	utf16le := func(s string, bom bool) string {
+		var b []byte
+		if bom {
+			b = append(b, 0xFF, 0xFE)
+		}
+		for _, u := range utf16.Encode([]rune(s)) {
+			b = append(b, byte(u), byte(u>>8))
+		}
+		return string(b)
+	}
+
+	t.Run("utf-16le with bom", func(t *testing.T) {
+		env, err := ParseString(utf16le("KEY=héllo\r\nB=2\r\n", true), WithEncoding(EncodingAuto))
+		assertNoError(t, err)
+		assertEqual(t, env["KEY"], "héllo")
+		assertEqual(t, env["B"], "2")
+	})
+
+	t.Run("utf-16be with bom", func(t *testing.T) {
+		env, err := ParseString("\xFE\xFF\x00K\x00=\x00v\x00\n", WithEncoding(EncodingAuto))
+		assertNoError(t, err)
+		assertEqual(t, env["K"], "v")
+	})
+
+	t.Run("utf-8 bom is dropped", func(t *testing.T) {
+		env, err := ParseString("\xEF\xBB\xBFKEY=1\n", WithEncoding(EncodingAuto))
+		assertNoError(t, err)
+		assertEqual(t, env["KEY"], "1")
+	})
+
+	t.Run("invalid utf-8 falls back to latin-1", func(t *testing.T) {
+		env, err := ParseString("NAME=caf\xe9\n", WithEncoding(EncodingAuto))
+		assertNoError(t, err)
+		assertEqual(t, env["NAME"], "café")
+	})
+
+	t.Run("explicit utf-16le without bom", func(t *testing.T) {
+		env, err := ParseString(utf16le("A=1\n", false), WithEncoding(EncodingUTF16LE))
+		assertNoError(t, err)
+		assertEqual(t, env["A"], "1")
+	})
+
+	t.Run("odd utf-16 length", func(t *testing.T) {
+		_, err := ParseString("\xFF\xFEA", WithEncoding(EncodingAuto))
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package dotenv
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// EncryptedPrefix marks values encrypted with Encrypt.
+const EncryptedPrefix = "encrypted:"
+
+// PublicKeyName is the key that holds the public key values of a file are
+// encrypted to, conventionally at the top of the file.
+const PublicKeyName = "DOTENV_PUBLIC_KEY"
+
+// ErrDecrypt is returned when an encrypted value can't be decrypted.
+var ErrDecrypt = errors.New("decryption failed")
+
+// WithDecryption decrypts values written by Encrypt while parsing, so that
+// a file can be committed with its secrets encrypted to the public key in
+// its header:
+//
+//	DOTENV_PUBLIC_KEY="3c1a…"
+//	API_KEY="encrypted:BASE64…"
+//
+// The private key is taken from DOTENV_PRIVATE_KEY_<ENV> for a file named
+// .env.<env>, such as DOTENV_PRIVATE_KEY_PRODUCTION for .env.production, and
+// from DOTENV_PRIVATE_KEY otherwise, or from the KeyProvider under the same
+// names. Several comma separated keys may be given. Use WithPrivateKey to
+// pass the key directly.
+func WithDecryption() Option {
+	return func(o *Options) {
+		o.Decryption = true
+	}
+}
+
+// WithPrivateKey enables decryption like WithDecryption, using the given
+// hex private keys for every file instead of the environment.
+func WithPrivateKey(keys ...string) Option {
+	return func(o *Options) {
+		o.Decryption = true
+		o.PrivateKeys = append(o.PrivateKeys, keys...)
+	}
+}
+
+// GenerateKeyPair returns a new hex encoded key pair for Encrypt and
+// Decrypt.
+func GenerateKeyPair() (publicKey, privateKey string, err error) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(key.PublicKey().Bytes()), hex.EncodeToString(key.Bytes()), nil
+}
+
+// Encrypt encrypts value to the hex publicKey and returns it with
+// EncryptedPrefix. Every call uses a fresh ephemeral X25519 key; the value
+// is sealed with AES-256-GCM under a key derived from the shared secret
+// with HKDF-SHA256.
+func Encrypt(value, publicKey string) (string, error) {
+	pub, err := parseKey(publicKey, func(b []byte) (*ecdh.PublicKey, error) {
+		return ecdh.X25519().NewPublicKey(b)
+	})
+	if err != nil {
+		return "", fmt.Errorf("invalid public key: %w", err)
+	}
+	eph, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := valueCipher(eph, pub, eph.PublicKey())
+	if err != nil {
+		return "", err
+	}
+
+	out := append([]byte{}, eph.PublicKey().Bytes()...)
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	out = append(out, nonce...)
+	out = gcm.Seal(out, nonce, []byte(value), nil)
+	return EncryptedPrefix + base64.StdEncoding.EncodeToString(out), nil
+}
+
+// Decrypt reverses Encrypt with the hex privateKey. The EncryptedPrefix is
+// optional. A wrong key or tampered value fails with ErrDecrypt.
+func Decrypt(value, privateKey string) (string, error) {
+	priv, err := parseKey(privateKey, ecdh.X25519().NewPrivateKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid private key: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, EncryptedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrDecrypt, err)
+	}
+	const keySize = 32
+	if len(data) < keySize {
+		return "", fmt.Errorf("%w: value too short", ErrDecrypt)
+	}
+	eph, err := ecdh.X25519().NewPublicKey(data[:keySize])
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrDecrypt, err)
+	}
+	gcm, err := valueCipher(priv, eph, eph)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrDecrypt, err)
+	}
+	data = data[keySize:]
+	if len(data) < gcm.NonceSize()+gcm.Overhead() {
+		return "", fmt.Errorf("%w: value too short", ErrDecrypt)
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	return string(plain), nil
+}
+
+// valueCipher derives the AES-GCM cipher for a value from the shared secret
+// of priv and pub, bound to the ephemeral public key.
+func valueCipher(priv *ecdh.PrivateKey, pub, eph *ecdh.PublicKey) (cipher.AEAD, error) {
+	secret, err := priv.ECDH(pub)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hkdf.Key(sha256.New, secret, eph.Bytes(), "dotenv encrypted value", 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func parseKey[K any](s string, parse func([]byte) (K, error)) (K, error) {
+	b, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		var zero K
+		return zero, err
+	}
+	return parse(b)
+}
+
+// PrivateKeyName returns the variable WithDecryption reads the private key
+// for envPath from: DOTENV_PRIVATE_KEY_<ENV> for a file named .env.<env>
+// and DOTENV_PRIVATE_KEY otherwise.
+func PrivateKeyName(envPath string) string {
+	if env, ok := strings.CutPrefix(path.Base(envPath), ".env."); ok && env != "" {
+		return "DOTENV_PRIVATE_KEY_" + NormalizeUpperSnake(env)
+	}
+	return "DOTENV_PRIVATE_KEY"
+}
+
+// privateKeys returns the private keys to try for envPath.
+func (o Options) privateKeys(ctx context.Context, envPath string) ([]string, error) {
+	if len(o.PrivateKeys) > 0 {
+		return o.PrivateKeys, nil
+	}
+	names := []string{"DOTENV_PRIVATE_KEY"}
+	if name := PrivateKeyName(envPath); name != names[0] {
+		names = append([]string{name}, names...)
+	}
+	for _, name := range names {
+		keys, err := o.key(ctx, name)
+		if err != nil || keys != "" {
+			return strings.Split(keys, ","), err
+		}
+	}
+	return nil, nil
+}
+
+// decryptValue decrypts an encrypted value from envPath with the first key
+// that fits.
+func (o Options) decryptValue(ctx context.Context, envPath, value string) (string, error) {
+	keys, err := o.privateKeys(ctx, envPath)
+	if err != nil {
+		return "", err
+	}
+	if len(keys) == 0 {
+		return "", fmt.Errorf("%w: no private key, set DOTENV_PRIVATE_KEY", ErrDecrypt)
+	}
+	for _, key := range keys {
+		var plain string
+		if plain, err = Decrypt(value, key); err == nil {
+			return plain, nil
+		}
+	}
+	return "", err
+}
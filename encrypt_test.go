@@ -0,0 +1,95 @@
+package dotenv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_encryptDecrypt(t *testing.T) {
+	pub, priv, err := GenerateKeyPair()
+	assertNoError(t, err)
+
+	enc, err := Encrypt("s3cr3t", pub)
+	assertNoError(t, err)
+	if !strings.HasPrefix(enc, EncryptedPrefix) || strings.Contains(enc, "s3cr3t") {
+		t.Fatalf("unexpected ciphertext %q", enc)
+	}
+	again, err := Encrypt("s3cr3t", pub)
+	assertNoError(t, err)
+	if again == enc {
+		t.Fatal("encryption should not be deterministic")
+	}
+
+	plain, err := Decrypt(enc, priv)
+	assertNoError(t, err)
+	assertEqual(t, plain, "s3cr3t")
+
+	_, other, err := GenerateKeyPair()
+	assertNoError(t, err)
+	if _, err := Decrypt(enc, other); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("want ErrDecrypt, got %v", err)
+	}
+	if _, err := Decrypt(enc[:len(enc)-4]+"AAA=", priv); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("want ErrDecrypt, got %v", err)
+	}
+	if _, err := Encrypt("x", "nothex"); err == nil {
+		t.Fatal("expected invalid key error")
+	}
+}
+
+func Test_decryption(t *testing.T) {
+	pub, priv, err := GenerateKeyPair()
+	assertNoError(t, err)
+	prodPub, prodPriv, err := GenerateKeyPair()
+	assertNoError(t, err)
+	token, _ := Encrypt("tok", pub)
+	prodToken, _ := Encrypt("prod-tok", prodPub)
+
+	fs := fstest.MapFS{
+		".env":            &fstest.MapFile{Data: []byte("DOTENV_PUBLIC_KEY=" + pub + "\nTOKEN=\"" + token + "\"\nURL=https://x?t=${TOKEN}\n")},
+		".env.production": &fstest.MapFile{Data: []byte("DOTENV_PUBLIC_KEY=" + prodPub + "\nTOKEN=" + prodToken + "\n")},
+	}
+
+	t.Run("key from the environment", func(t *testing.T) {
+		t.Setenv("DOTENV_PRIVATE_KEY", priv)
+		t.Setenv("DOTENV_PRIVATE_KEY_PRODUCTION", prodPriv)
+		env, err := Parse(WithFs(fs), WithDecryption(), WithExpand(true))
+		assertNoError(t, err)
+		assertEqual(t, env["TOKEN"], "tok")
+		assertEqual(t, env["URL"], "https://x?t=tok")
+
+		env, err = Parse(WithFs(fs), WithFilename(".env.production"), WithDecryption())
+		assertNoError(t, err)
+		assertEqual(t, env["TOKEN"], "prod-tok")
+	})
+
+	t.Run("explicit keys", func(t *testing.T) {
+		env, err := Parse(WithFs(fs), WithPaths(".env", ".env.production"), WithPrivateKey(prodPriv, priv))
+		assertNoError(t, err)
+		assertEqual(t, env["TOKEN"], "prod-tok")
+	})
+
+	t.Run("left alone without the option", func(t *testing.T) {
+		env, err := Parse(WithFs(fs))
+		assertNoError(t, err)
+		assertEqual(t, env["TOKEN"], token)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		t.Setenv("DOTENV_PRIVATE_KEY", "")
+		_, err := Parse(WithFs(fs), WithDecryption())
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Line != 2 || !strings.Contains(perr.Reason, "no private key") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func Test_privateKeyName(t *testing.T) {
+	assertEqual(t, PrivateKeyName(".env"), "DOTENV_PRIVATE_KEY")
+	assertEqual(t, PrivateKeyName("config/.env.production"), "DOTENV_PRIVATE_KEY_PRODUCTION")
+	assertEqual(t, PrivateKeyName(".env.ci-staging"), "DOTENV_PRIVATE_KEY_CI_STAGING")
+	assertEqual(t, PrivateKeyName("secrets.env"), "DOTENV_PRIVATE_KEY")
+}
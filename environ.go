@@ -0,0 +1,92 @@
+package dotenv
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// Environ returns os.Environ() with the parsed values merged over it, in the
+// "KEY=value" form used by exec.Cmd.Env. The process environment itself is
+// not modified. With WithNoOverride, variables already present win.
+func Environ(userOptions ...Option) ([]string, error) {
+	return environ(os.Environ(), userOptions)
+}
+
+// ApplyToCmd merges the parsed values into cmd.Env so that the child process
+// sees them while the parent environment stays untouched. When cmd.Env is
+// nil, the values are merged over os.Environ(), matching what the child would
+// otherwise inherit.
+func ApplyToCmd(cmd *exec.Cmd, userOptions ...Option) error {
+	base := cmd.Env
+	if base == nil {
+		base = os.Environ()
+	}
+	env, err := environ(base, userOptions)
+	if err != nil {
+		return err
+	}
+	cmd.Env = env
+	return nil
+}
+
+func environ(base []string, userOptions []Option) ([]string, error) {
+	opts, err := buildOptions(userOptions)
+	if err != nil {
+		return nil, fmt.Errorf("can't build environment with these options: %w", err)
+	}
+
+	env, err := parse(context.Background(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := mergeEnviron(base, env.values(), opts.NoOverride)
+	if !opts.NoOverride {
+		merged = slices.DeleteFunc(merged, func(kv string) bool {
+			e, ok := env[environKey(kv)]
+			return ok && e.unset
+		})
+	}
+	return merged, nil
+}
+
+// mergeEnviron replaces or appends values in a copy of base. Existing entries
+// keep their position; new keys are appended in sorted order.
+func mergeEnviron(base []string, values map[string]string, noOverride bool) []string {
+	merged := make([]string, 0, len(base)+len(values))
+	seen := make(map[string]bool, len(values))
+	for _, kv := range base {
+		key := environKey(kv)
+		if val, ok := values[key]; ok && !seen[key] {
+			seen[key] = true
+			if !noOverride {
+				kv = key + "=" + val
+			}
+		}
+		merged = append(merged, kv)
+	}
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		if !seen[key] {
+			merged = append(merged, key+"="+values[key])
+		}
+	}
+	return merged
+}
+
+// environKey returns the key of a "KEY=value" entry. The first byte is never
+// treated as the separator: on Windows, entries like "=C:=C:\dir" start with
+// '=', which is part of the key.
+func environKey(kv string) string {
+	if kv == "" {
+		return ""
+	}
+	if i := strings.IndexByte(kv[1:], '='); i >= 0 {
+		return kv[:i+1]
+	}
+	return kv
+}
@@ -0,0 +1,55 @@
+package dotenv
+
+import (
+	"os"
+	"os/exec"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_environ(t *testing.T) {
+	fs := fstest.MapFS{
+		".env": &fstest.MapFile{Data: []byte("ENVIRON_NEW=file\nENVIRON_SET=file\n")},
+	}
+
+	t.Run("merges over process environment without mutating it", func(t *testing.T) {
+		t.Setenv("ENVIRON_SET", "process")
+		os.Unsetenv("ENVIRON_NEW")
+
+		env, err := Environ(WithFs(fs))
+		assertNoError(t, err)
+		assertEqual(t, slices.Contains(env, "ENVIRON_NEW=file"), true)
+		assertEqual(t, slices.Contains(env, "ENVIRON_SET=file"), true)
+		assertEqual(t, slices.Contains(env, "ENVIRON_SET=process"), false)
+		assertEqual(t, os.Getenv("ENVIRON_SET"), "process")
+		_, set := os.LookupEnv("ENVIRON_NEW")
+		assertEqual(t, set, false)
+	})
+
+	t.Run("no override keeps existing entries", func(t *testing.T) {
+		t.Setenv("ENVIRON_SET", "process")
+
+		env, err := Environ(WithFs(fs), WithNoOverride())
+		assertNoError(t, err)
+		assertEqual(t, slices.Contains(env, "ENVIRON_SET=process"), true)
+		assertEqual(t, slices.Contains(env, "ENVIRON_NEW=file"), true)
+	})
+
+	t.Run("applies to command env", func(t *testing.T) {
+		cmd := exec.Command("true")
+		cmd.Env = []string{"ENVIRON_SET=cmd", "OTHER=1"}
+
+		err := ApplyToCmd(cmd, WithFs(fs))
+		assertNoError(t, err)
+		assertEqual(t, slices.Equal(cmd.Env, []string{"ENVIRON_SET=file", "OTHER=1", "ENVIRON_NEW=file"}), true)
+	})
+}
+
+func Test_environKey(t *testing.T) {
+	assertEqual(t, environKey("A=1"), "A")
+	assertEqual(t, environKey("A=b=c"), "A")
+	assertEqual(t, environKey("=C:=C:\\dir"), "=C:")
+	assertEqual(t, environKey("NOVALUE"), "NOVALUE")
+	assertEqual(t, environKey(""), "")
+}
@@ -0,0 +1,37 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// Sentinel errors for telling failures apart with errors.Is. The errors
+// carrying details match them: *PathError matches ErrPathNotFound,
+// *ParseError matches ErrParse, and *ValidationError and *MissingKeysError
+// match ErrValidation.
+var (
+	ErrPathNotFound = errors.New("path not found")
+	ErrParse        = errors.New("parse error")
+	ErrValidation   = errors.New("validation failed")
+)
+
+// PathError reports a path required by WithRequiredPaths that does not
+// exist. It matches ErrPathNotFound and fs.ErrNotExist.
+type PathError struct {
+	Path string
+	// Filenames lists the names looked for when Path is a directory.
+	Filenames []string
+}
+
+func (e *PathError) Error() string {
+	if len(e.Filenames) > 0 {
+		return fmt.Sprintf("no dotenv file %v in %s: %v", e.Filenames, e.Path, ErrPathNotFound)
+	}
+	return fmt.Sprintf("%s: %v", e.Path, ErrPathNotFound)
+}
+
+// Is reports whether target is ErrPathNotFound or fs.ErrNotExist.
+func (e *PathError) Is(target error) bool {
+	return target == ErrPathNotFound || target == fs.ErrNotExist
+}
@@ -0,0 +1,56 @@
+package dotenv
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_typedErrors(t *testing.T) {
+	files := fstest.MapFS{
+		".env":    &fstest.MapFile{Data: []byte("A=1\n")},
+		"bad.env": &fstest.MapFile{Data: []byte("A=1\nA=2\n")},
+		"dir/x":   &fstest.MapFile{Data: []byte("")},
+	}
+
+	t.Run("missing paths", func(t *testing.T) {
+		_, err := Parse(WithFs(files), WithPaths("nope.env"), WithRequiredPaths())
+		var perr *PathError
+		if !errors.As(err, &perr) || perr.Path != "nope.env" {
+			t.Fatalf("expected *PathError, got %v", err)
+		}
+		assertEqual(t, errors.Is(err, ErrPathNotFound), true)
+		assertEqual(t, errors.Is(err, fs.ErrNotExist), true)
+		assertEqual(t, errors.Is(err, ErrParse), false)
+
+		_, err = Parse(WithFs(files), WithPaths("dir"), WithRequiredPaths())
+		if !errors.As(err, &perr) || len(perr.Filenames) != 1 {
+			t.Fatalf("expected *PathError with filenames, got %v", err)
+		}
+		assertEqual(t, err.Error(), "no dotenv file [.env] in dir: path not found")
+	})
+
+	t.Run("parse errors", func(t *testing.T) {
+		_, err := Parse(WithFs(files), WithPaths("bad.env"), WithDuplicatePolicy(DuplicateError))
+		assertEqual(t, errors.Is(err, ErrParse), true)
+		assertEqual(t, errors.Is(err, ErrPathNotFound), false)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("expected *ParseError, got %v", err)
+		}
+		assertEqual(t, perr.Key, "A")
+		assertEqual(t, perr.Line, 2)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		_, err := Parse(WithFs(files), WithRequired("MISSING_TYPED"))
+		assertEqual(t, errors.Is(err, ErrValidation), true)
+
+		schema := NewSchema()
+		schema.Key("A").OneOf("x")
+		_, err = Parse(WithFs(files), WithSchema(schema))
+		assertEqual(t, errors.Is(err, ErrValidation), true)
+		assertEqual(t, errors.Is(err, ErrParse), false)
+	})
+}
@@ -0,0 +1,129 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ExampleError reports how a dotenv file and its example, such as
+// .env.example, disagree on the keys they define. It matches ErrValidation.
+type ExampleError struct {
+	// Undocumented lists keys of the dotenv file missing from the example.
+	Undocumented []string
+	// Missing lists keys of the example the dotenv file doesn't define.
+	Missing []string
+}
+
+func (e *ExampleError) Error() string {
+	var parts []string
+	if len(e.Undocumented) > 0 {
+		parts = append(parts, "not in example: "+strings.Join(e.Undocumented, ", "))
+	}
+	if len(e.Missing) > 0 {
+		parts = append(parts, "missing from env: "+strings.Join(e.Missing, ", "))
+	}
+	return "example out of sync: " + strings.Join(parts, "; ")
+}
+
+// Is reports whether target is ErrValidation.
+func (e *ExampleError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// CheckExample compares the keys defined in the dotenv file envPath with
+// those in examplePath and returns an *ExampleError listing the keys only
+// one of them defines, or nil when they agree. Values are ignored. It is
+// meant for CI, to catch variables added without documenting them:
+//
+//	if err := dotenv.CheckExample(".env", ".env.example"); err != nil {
+//		log.Fatal(err)
+//	}
+func CheckExample(envPath, examplePath string) error {
+	env, err := readDocument(envPath)
+	if err != nil {
+		return err
+	}
+	example, err := readDocument(examplePath)
+	if err != nil {
+		return err
+	}
+
+	envKeys, exampleKeys := env.Keys(), example.Keys()
+	e := &ExampleError{}
+	for _, key := range envKeys {
+		if !slices.Contains(exampleKeys, key) {
+			e.Undocumented = append(e.Undocumented, key)
+		}
+	}
+	for _, key := range exampleKeys {
+		if !slices.Contains(envKeys, key) {
+			e.Missing = append(e.Missing, key)
+		}
+	}
+	if len(e.Undocumented) == 0 && len(e.Missing) == 0 {
+		return nil
+	}
+	return e
+}
+
+// WriteExample creates or updates examplePath so that it defines the same
+// keys as the dotenv file envPath, without values. A new example is a copy
+// of envPath, comments included, with every value blanked. An existing
+// example keeps its comments and placeholder values; keys envPath no longer
+// defines are removed and new ones are appended blank.
+func WriteExample(envPath, examplePath string) error {
+	env, err := readDocument(envPath)
+	if err != nil {
+		return err
+	}
+	example, err := readDocument(examplePath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		example = env
+		for i := range example.stmts {
+			blankValue(&example.stmts[i])
+		}
+	case err != nil:
+		return err
+	default:
+		envKeys := env.Keys()
+		for _, key := range example.Keys() {
+			if !slices.Contains(envKeys, key) {
+				example.Delete(key)
+			}
+		}
+		for _, key := range envKeys {
+			if _, ok := example.Get(key); !ok {
+				example.stmts = append(example.stmts, statement{key: key, raw: key + "=", valueCol: len(key) + 2})
+			}
+		}
+	}
+	return example.Save(examplePath)
+}
+
+// blankValue removes the value of st, keeping its prefix and comment.
+func blankValue(st *statement) {
+	if st.key == "" {
+		return
+	}
+	st.value, st.quote = "", 0
+	st.raw = st.prefix + st.key + "=" + st.comment
+	st.valueCol = len(st.prefix) + len(st.key) + 2
+}
+
+func readDocument(filename string) (*Document, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	doc, err := ParseDocument(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return doc, nil
+}
@@ -0,0 +1,61 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_example(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	write := func(name, data string) {
+		t.Helper()
+		assertNoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600))
+	}
+	read := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		assertNoError(t, err)
+		return string(data)
+	}
+	write(".env", "# database\nDB_HOST=localhost # local only\nDB_PASS='s3cret'\n\nexport API_KEY=abc\n")
+
+	t.Run("generates a blank example", func(t *testing.T) {
+		_, err := os.Stat(".env.example")
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("unexpected example: %v", err)
+		}
+		assertNoError(t, WriteExample(".env", ".env.example"))
+		assertEqual(t, read(".env.example"), "# database\nDB_HOST= # local only\nDB_PASS=\n\nexport API_KEY=\n")
+		assertNoError(t, CheckExample(".env", ".env.example"))
+	})
+
+	t.Run("reports keys out of sync", func(t *testing.T) {
+		write(".env.example", "# database\nDB_HOST=example.com\nDB_PASS=\nOLD=\n")
+		err := CheckExample(".env", ".env.example")
+		var exErr *ExampleError
+		if !errors.As(err, &exErr) || !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected *ExampleError, got %v", err)
+		}
+		assertEqual(t, len(exErr.Undocumented), 1)
+		assertEqual(t, exErr.Undocumented[0], "API_KEY")
+		assertEqual(t, len(exErr.Missing), 1)
+		assertEqual(t, exErr.Missing[0], "OLD")
+		assertEqual(t, err.Error(), "example out of sync: not in example: API_KEY; missing from env: OLD")
+	})
+
+	t.Run("updates an existing example", func(t *testing.T) {
+		assertNoError(t, WriteExample(".env", ".env.example"))
+		assertEqual(t, read(".env.example"), "# database\nDB_HOST=example.com\nDB_PASS=\nAPI_KEY=\n")
+		assertNoError(t, CheckExample(".env", ".env.example"))
+	})
+
+	t.Run("missing files", func(t *testing.T) {
+		err := CheckExample(".env", "nope")
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("expected not exist error, got %v", err)
+		}
+	})
+}
@@ -40,3 +40,18 @@ func Example_withOverride() {
 	fmt.Println(os.Getenv("KEY"))
 	// Output: two
 }
+
+// ExampleParse reads values into a map without exporting them.
+func ExampleParse() {
+	fs := fstest.MapFS{
+		".env": &fstest.MapFile{Data: []byte("HOST=localhost\nPORT=8080\n")},
+	}
+
+	env, err := dotenv.Parse(dotenv.WithFs(fs))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(env["HOST"], env["PORT"])
+	// Output: localhost 8080
+}
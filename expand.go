@@ -0,0 +1,183 @@
+package dotenv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// expander resolves references inside values.
+type expander struct {
+	lookup func(name string) (string, bool, error)
+	// run executes $(...) command substitutions; nil leaves them literal.
+	run func(command string) (string, error)
+}
+
+// expand replaces $VAR and ${VAR} references in s using lookup. References to
+// unknown variables expand to an empty string; lookup errors abort. A
+// backslash in front of '$' keeps the dollar sign literal.
+//
+// Braced references support the POSIX operators ${VAR:-default},
+// ${VAR:?message} and ${VAR:+alternative}. With the colon the operator treats
+// an empty value like an unset one; without it only unset variables count.
+// The operator words are expanded themselves, so they may nest references.
+//
+// With a runner set, $(command) is replaced by the command output without
+// trailing newlines.
+func (x expander) expand(s string) (string, error) {
+	if !strings.ContainsRune(s, '$') {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) && s[i+1] == '$' {
+			b.WriteByte('$')
+			i++
+			continue
+		}
+		if c != '$' || i+1 == len(s) {
+			b.WriteByte(c)
+			continue
+		}
+
+		if s[i+1] == '(' && x.run != nil {
+			end := matchingParen(s, i+2)
+			if end < 0 {
+				b.WriteString(s[i:])
+				break
+			}
+			out, err := x.run(s[i+2 : end])
+			if err != nil {
+				return "", fmt.Errorf("command substitution $(%s): %w", s[i+2:end], err)
+			}
+			b.WriteString(strings.TrimRight(out, "\r\n"))
+			i = end
+			continue
+		}
+
+		if s[i+1] == '{' {
+			end := matchingBrace(s, i+2)
+			if end < 0 {
+				b.WriteString(s[i:])
+				break
+			}
+			val, err := x.expandBraced(s[i+2 : end])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(val)
+			i = end
+			continue
+		}
+
+		n := nameLen(s[i+1:])
+		if n == 0 {
+			b.WriteByte(c)
+			continue
+		}
+		val, _, err := x.lookup(s[i+1 : i+1+n])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(val)
+		i += n
+	}
+	return b.String(), nil
+}
+
+// expandBraced expands the body of a ${...} reference.
+func (x expander) expandBraced(body string) (string, error) {
+	n := nameLen(body)
+	rest := body[n:]
+	colon := strings.HasPrefix(rest, ":")
+	if colon {
+		rest = rest[1:]
+	}
+	if n == 0 || rest == "" || !strings.ContainsRune("-?+", rune(rest[0])) {
+		val, _, err := x.lookup(body)
+		return val, err
+	}
+
+	name, op, word := body[:n], rest[0], rest[1:]
+	val, ok, err := x.lookup(name)
+	if err != nil {
+		return "", err
+	}
+	missing := !ok || (colon && val == "")
+	switch op {
+	case '-':
+		if missing {
+			return x.expand(word)
+		}
+		return val, nil
+	case '+':
+		if missing {
+			return "", nil
+		}
+		return x.expand(word)
+	default: // '?'
+		if !missing {
+			return val, nil
+		}
+		msg, err := x.expand(word)
+		if err != nil {
+			return "", err
+		}
+		if msg == "" {
+			msg = "parameter null or not set"
+		}
+		return "", fmt.Errorf("%s: %s", name, msg)
+	}
+}
+
+// matchingBrace returns the index of the '}' closing the reference whose body
+// starts at start, accounting for nested ${...}, or -1.
+func matchingBrace(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch {
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			depth++
+			i++
+		case s[i] == '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// matchingParen returns the index of the ')' closing the command
+// substitution whose body starts at start, accounting for nesting, or -1.
+func matchingParen(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// nameLen returns the length of the variable name at the start of s.
+func nameLen(s string) int {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' && i > 0:
+		default:
+			return i
+		}
+	}
+	return len(s)
+}
@@ -0,0 +1,57 @@
+package dotenv
+
+import (
+	"os"
+	"testing"
+)
+
+func Test_expandOperators(t *testing.T) {
+	vars := map[string]string{"SET": "value", "EMPTY": "", "FALLBACK": "fb"}
+	lookup := func(name string) (string, bool, error) {
+		v, ok := vars[name]
+		return v, ok, nil
+	}
+
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"${SET:-def}", "value"},
+		{"${UNSET:-def}", "def"},
+		{"${EMPTY:-def}", "def"},
+		{"${EMPTY-def}", ""},
+		{"${UNSET-def}", "def"},
+		{"${UNSET:-${FALLBACK}}", "fb"},
+		{"${UNSET:-${ALSO_UNSET:-deep}}/x", "deep/x"},
+		{"${SET:+alt}", "alt"},
+		{"${EMPTY:+alt}", ""},
+		{"${EMPTY+alt}", "alt"},
+		{"${UNSET:+alt}", ""},
+		{"${SET:?boom}", "value"},
+		{"${UNSET", "${UNSET"},
+	}
+	for _, tc := range cases {
+		got, err := expander{lookup: lookup}.expand(tc.in)
+		assertNoError(t, err)
+		if got != tc.want {
+			t.Fatalf("expand(%q): got=%q, want=%q", tc.in, got, tc.want)
+		}
+	}
+
+	_, err := expander{lookup: lookup}.expand("${UNSET:?must be set}")
+	if err == nil || err.Error() != "UNSET: must be set" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = expander{lookup: lookup}.expand("${EMPTY:?}")
+	if err == nil || err.Error() != "EMPTY: parameter null or not set" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func Test_expandRequiredInFile(t *testing.T) {
+	os.Unsetenv("DOTENV_TEST_MISSING")
+	_, err := ParseString("A=1\nB=x${DOTENV_TEST_MISSING:?is required}\n", WithExpand(true))
+	if err == nil || err.Error() != "<string>:2:3: DOTENV_TEST_MISSING: is required" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
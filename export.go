@@ -0,0 +1,98 @@
+package dotenv
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ToShell renders env as POSIX shell "export KEY='value'" lines with keys
+// sorted, ready to be sourced. Values are single-quoted, so nothing in them
+// is expanded by the shell. Keys that are not valid shell variable names
+// can't be exported and are written as comments instead.
+func ToShell(env map[string]string) string {
+	var b strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		if key == "" || nameLen(key) != len(key) {
+			b.WriteString("# skipped " + strings.ReplaceAll(key, "\n", " ") + ": not a valid shell variable name\n")
+			continue
+		}
+		b.WriteString("export ")
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(shellQuote(env[key]))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// shellQuote single-quotes s for POSIX shells. An embedded single quote
+// ends the quoting, is escaped with a backslash and quoting resumes.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// ToJSON renders env as an indented JSON object. Keys are sorted, so the
+// output is stable across runs.
+func ToJSON(env map[string]string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(env); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ToYAML renders env as a flat YAML mapping with keys sorted. Values are
+// always double-quoted so that YAML never reads them as numbers, booleans or
+// null; keys are quoted when needed.
+func ToYAML(env map[string]string) ([]byte, error) {
+	var b strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		k, err := yamlKey(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := yamlString(env[key])
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(k)
+		b.WriteString(": ")
+		b.WriteString(v)
+		b.WriteByte('\n')
+	}
+	return []byte(b.String()), nil
+}
+
+// yamlKey returns key as a plain scalar when YAML reads it back as the same
+// string, and quoted otherwise.
+func yamlKey(key string) (string, error) {
+	plain := key != "" && strings.IndexFunc(key, func(r rune) bool {
+		return !(r == '_' || r == '.' || r == '-' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}) < 0 && key[0] != '-'
+	switch strings.ToLower(key) {
+	case "y", "yes", "n", "no", "true", "false", "on", "off", "null":
+		plain = false
+	}
+	if plain {
+		return key, nil
+	}
+	return yamlString(key)
+}
+
+// yamlString double-quotes s. JSON strings are valid YAML double-quoted
+// scalars.
+func yamlString(s string) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
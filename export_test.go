@@ -0,0 +1,59 @@
+package dotenv
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func Test_toShell(t *testing.T) {
+	env := map[string]string{
+		"PLAIN":   "value",
+		"QUOTES":  `it's "$HOME"`,
+		"NEWLINE": "a\nb",
+		"db.host": "x",
+	}
+	got := ToShell(env)
+	want := "export NEWLINE='a\nb'\n" +
+		"export PLAIN='value'\n" +
+		"export QUOTES='it'\\''s \"$HOME\"'\n" +
+		"# skipped db.host: not a valid shell variable name\n"
+	assertEqual(t, got, want)
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	out, err := exec.Command("sh", "-c", got+`printf '%s|%s' "$QUOTES" "$NEWLINE"`).Output()
+	assertNoError(t, err)
+	assertEqual(t, string(out), "it's \"$HOME\"|a\nb")
+}
+
+func Test_toJSON(t *testing.T) {
+	out, err := ToJSON(map[string]string{"B": "2", "A": "<1>"})
+	assertNoError(t, err)
+	assertEqual(t, string(out), "{\n  \"A\": \"<1>\",\n  \"B\": \"2\"\n}\n")
+}
+
+func Test_toYAML(t *testing.T) {
+	env := map[string]string{
+		"PORT":    "08",
+		"ON":      "true",
+		"MULTI":   "a\nb",
+		"db.host": "x",
+		"with sp": "y",
+	}
+	out, err := ToYAML(env)
+	assertNoError(t, err)
+	assertEqual(t, string(out), `MULTI: "a\nb"
+"ON": "true"
+PORT: "08"
+db.host: "x"
+"with sp": "y"
+`)
+
+	back, err := ParseString(string(out), WithFormat(FormatYAML))
+	assertNoError(t, err)
+	assertEqual(t, len(back), len(env))
+	for k, v := range env {
+		assertEqual(t, back[k], v)
+	}
+}
@@ -0,0 +1,128 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WithConcurrency reads up to n sources at once: files, key directories and
+// providers, including provider URLs listed among the paths. Values are
+// still merged in declared order, so the result is the same as reading them
+// one by one; this only cuts the time spent waiting on slow backends. When
+// one source fails without WithStrict, sources not started yet are skipped.
+// The Logger, Tracer and providers must be safe for concurrent use when
+// n > 1. Sources are read one at a time by default.
+func WithConcurrency(n int) Option {
+	return func(o *Options) {
+		o.Concurrency = n
+	}
+}
+
+// source is one read of parse: a file, key directory or provider.
+type source struct {
+	name string
+	read func(ctx context.Context, raws *[]rawEntry) error
+	// collect marks failures that strict mode collects instead of stopping.
+	collect bool
+	// missing records a path that resolved to no files.
+	missing bool
+
+	raws     []rawEntry
+	err      error
+	duration time.Duration
+	// skipped is set when the source was not read because another failed.
+	skipped bool
+}
+
+// sourceReader reads the sources added to it, up to opts.Concurrency at
+// once. A failure that ends parsing cancels the sources still running and
+// skips those added afterwards.
+type sourceReader struct {
+	parent  context.Context
+	ctx     context.Context
+	cancel  context.CancelFunc
+	opts    Options
+	srcs    []*source
+	sem     chan struct{}
+	wg      sync.WaitGroup
+	aborted atomic.Bool
+}
+
+func newSourceReader(ctx context.Context, opts Options) *sourceReader {
+	r := &sourceReader{parent: ctx, opts: opts, sem: make(chan struct{}, max(opts.Concurrency, 1))}
+	r.ctx, r.cancel = context.WithCancel(ctx)
+	return r
+}
+
+// stopped reports whether a failure ended parsing, so that no more sources
+// need to be listed.
+func (r *sourceReader) stopped() bool {
+	return r.aborted.Load()
+}
+
+// add reads src, in the background unless sources are read one at a time.
+func (r *sourceReader) add(src *source) {
+	r.srcs = append(r.srcs, src)
+	if src.read == nil {
+		return
+	}
+	r.sem <- struct{}{}
+	if r.ctx.Err() != nil {
+		<-r.sem
+		src.skipped = true
+		return
+	}
+	run := func() {
+		defer func() { <-r.sem }()
+		readSource(r.ctx, r.opts, src)
+		if src.err != nil && (!r.opts.Strict || !src.collect) {
+			r.aborted.Store(true)
+			r.cancel()
+		}
+	}
+	if cap(r.sem) == 1 {
+		run()
+		return
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		run()
+	}()
+}
+
+// wait waits for the sources to be read and returns them in the order they
+// were added.
+func (r *sourceReader) wait() []*source {
+	r.wg.Wait()
+	r.cancel()
+	// Sources interrupted because another one failed were not really read.
+	if r.aborted.Load() && r.parent.Err() == nil {
+		for _, src := range r.srcs {
+			if src.err != nil && errors.Is(src.err, context.Canceled) {
+				src.skipped = true
+			}
+		}
+	}
+	return r.srcs
+}
+
+// readSource reads src, retrying it according to opts. Every source is read
+// into its own slice so that a failed attempt leaves nothing behind when it
+// is retried.
+func readSource(ctx context.Context, opts Options, src *source) {
+	start := time.Now()
+	ctx, span := opts.startSpan(ctx, "dotenv.read")
+	span.SetAttributes("source", src.name)
+	src.raws, src.err = retry(ctx, opts, src.name, func(ctx context.Context) ([]rawEntry, error) {
+		var rs []rawEntry
+		err := src.read(ctx, &rs)
+		return rs, err
+	})
+	src.duration = time.Since(start)
+	span.SetAttributes("keys", len(src.raws))
+	span.End(src.err)
+}
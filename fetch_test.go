@@ -0,0 +1,90 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func Test_concurrency(t *testing.T) {
+	t.Run("reads sources at once and merges in order", func(t *testing.T) {
+		started := make(chan struct{}, 3)
+		all := make(chan struct{})
+		provider := func(value string, delay time.Duration) Provider {
+			return ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+				started <- struct{}{}
+				select {
+				case <-all:
+				case <-time.After(5 * time.Second):
+					return nil, errors.New("sources were not read concurrently")
+				}
+				time.Sleep(delay)
+				return map[string]string{"KEY": value}, nil
+			})
+		}
+		go func() {
+			for range 3 {
+				<-started
+			}
+			close(all)
+		}()
+
+		env, err := Parse(WithFs(fstest.MapFS{}), WithConcurrency(3), WithProviders(
+			provider("first", 0),
+			provider("second", 10*time.Millisecond),
+			provider("third", 0),
+		))
+		assertNoError(t, err)
+		assertEqual(t, env["KEY"], "third")
+	})
+
+	t.Run("bounds the sources read at once", func(t *testing.T) {
+		var running, peak atomic.Int32
+		provider := ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			return nil, nil
+		})
+
+		_, err := Parse(WithFs(fstest.MapFS{}), WithConcurrency(2),
+			WithProviders(provider, provider, provider, provider, provider))
+		assertNoError(t, err)
+		if p := peak.Load(); p > 2 {
+			t.Fatalf("%d sources read at once, want at most 2", p)
+		}
+	})
+
+	t.Run("reports the failing source", func(t *testing.T) {
+		fs := fstest.MapFS{
+			"a/.env": &fstest.MapFile{Data: []byte("A=1\n")},
+			"b/.env": &fstest.MapFile{Data: []byte("B=2\n")},
+		}
+		boom := errors.New("boom")
+		slow := ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		failing := ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+			return nil, boom
+		})
+
+		report, err := LoadReport(WithFs(fs), WithPaths("a", "b"), WithConcurrency(4),
+			WithProviders(slow, failing), WithSetter(func(string, string) error { return nil }))
+		if !errors.Is(err, boom) {
+			t.Fatalf("expected boom, got %v", err)
+		}
+		last := report.Files[len(report.Files)-1]
+		assertEqual(t, last.Status, FileFailed)
+		assertEqual(t, last.Error, "fetch dotenv.ProviderFunc: boom")
+	})
+}
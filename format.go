@@ -0,0 +1,165 @@
+package dotenv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Format selects the file format of the configured files. Whatever the
+// format, values go through the same merging, expansion and override rules.
+type Format int
+
+const (
+	// FormatDotenv parses files as dotenv files using the selected dialect.
+	FormatDotenv Format = iota
+	// FormatAuto picks the format by file extension and falls back to
+	// FormatDotenv.
+	FormatAuto
+	// FormatJSON reads a JSON object. Nested objects and arrays are
+	// flattened as described for WithJSONValues, with the whole path in
+	// upper snake case, so {"db": {"host": "x"}} yields DB_HOST; top-level
+	// keys holding scalars keep their names. Numbers, booleans and null
+	// become strings. Values are never expanded.
+	FormatJSON
+	// FormatYAML reads a YAML mapping. Nested keys are joined with '.' and
+	// sequence items get their index, so "db:\n  hosts:\n    - a" yields
+	// db.hosts.0=a; combine it with WithKeyNormalizer(NormalizeUpperSnake) to
+	// get DB_HOSTS_0. Values are never expanded. Only the subset of YAML used
+	// for configuration is supported: block mappings, sequences of scalars,
+	// plain, quoted and block (| and >) scalars, comments and a leading
+	// "---". Anchors, tags and non-empty flow collections are rejected.
+	FormatYAML
+	// FormatTOML reads a TOML document. Keys inside tables are exported as
+	// TABLE_KEY in upper snake case, like "[database]" and "host" becoming
+	// DATABASE_HOST; top-level keys keep their names. Arrays yield KEY_0,
+	// KEY_1 and so on and inline tables are flattened like tables. Numbers,
+	// booleans and dates are kept as written, minus digit separators. Arrays
+	// of tables are not supported. Values are never expanded.
+	FormatTOML
+	// FormatProperties reads a Java .properties file: "key=value",
+	// "key: value" and "key value" lines, '#' and '!' comments, backslash
+	// line continuations and \uXXXX escapes. Keys keep their names, such as
+	// db.url; see WithKeyNormalizer. Values are never expanded.
+	FormatProperties
+)
+
+// WithFormat sets the format of the files read, FormatDotenv by default.
+func WithFormat(f Format) Option {
+	return func(o *Options) {
+		o.Format = f
+	}
+}
+
+// formatOf returns the format used for the file at name.
+func (o Options) formatOf(name string) Format {
+	if o.Format != FormatAuto {
+		return o.Format
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	case ".properties":
+		return FormatProperties
+	}
+	return FormatDotenv
+}
+
+// scanner returns the function splitting files of format f into statements.
+func (f Format) scanner() func(Options, io.Reader, string, func(statement) error) error {
+	switch f {
+	case FormatJSON:
+		return scanJSON
+	case FormatYAML:
+		return scanYAML
+	case FormatTOML:
+		return scanTOML
+	case FormatProperties:
+		return scanProperties
+	}
+	return scanStatements
+}
+
+// scanJSON reads a JSON object and calls fn with a statement per leaf.
+func scanJSON(_ Options, r io.Reader, envPath string, fn func(statement) error) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", envPath, err)
+	}
+	errorAt := func(offset int64, reason string) error {
+		line, col := position(data, offset)
+		return &ParseError{File: envPath, Line: line, Col: col, Reason: reason}
+	}
+	syntaxError := func(err error) error {
+		var serr *json.SyntaxError
+		if errors.As(err, &serr) {
+			return errorAt(serr.Offset, serr.Error())
+		}
+		return errorAt(int64(len(data)), err.Error())
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return syntaxError(err)
+	}
+	if tok != json.Delim('{') {
+		return errorAt(dec.InputOffset(), "expected a JSON object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return syntaxError(err)
+		}
+		key := tok.(string)
+		line, _ := position(data, dec.InputOffset())
+
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return syntaxError(err)
+		}
+		switch v.(type) {
+		case map[string]any, []any:
+			key = NormalizeUpperSnake(key)
+		}
+		var stmts []statement
+		flattenJSON(key, v, func(key, value string) {
+			stmts = append(stmts, statement{line: line, key: key, value: value, quote: '\''})
+		})
+		for _, st := range stmts {
+			if err := fn(st); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return syntaxError(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		if err != nil {
+			return syntaxError(err)
+		}
+		return errorAt(dec.InputOffset(), "unexpected data after the JSON object")
+	}
+	return nil
+}
+
+// position converts a byte offset in data to a 1-based line and column.
+func position(data []byte, offset int64) (line, col int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	col = len(before) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
@@ -0,0 +1,81 @@
+package dotenv
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_formatJSON(t *testing.T) {
+	fs := fstest.MapFS{
+		".env": &fstest.MapFile{Data: []byte("A=from-env\nB=b\n")},
+		"config.json": &fstest.MapFile{Data: []byte(`{
+  "A": "from-json",
+  "PORT": 8080,
+  "DEBUG": false,
+  "REF": "$B",
+  "DB": {"host": "x"}
+}`)},
+	}
+
+	t.Run("merges with dotenv files", func(t *testing.T) {
+		report, err := LoadReport(WithPaths(".env", "config.json"), WithFs(fs), WithFormat(FormatAuto),
+			WithExpand(true), WithSetter(func(string, string) error { return nil }))
+		assertNoError(t, err)
+		assertEqual(t, len(report.Loaded), 6)
+
+		env, err := Parse(WithPaths(".env", "config.json"), WithFs(fs), WithFormat(FormatAuto), WithExpand(true))
+		assertNoError(t, err)
+		assertEqual(t, env["A"], "from-json")
+		assertEqual(t, env["B"], "b")
+		assertEqual(t, env["PORT"], "8080")
+		assertEqual(t, env["DEBUG"], "false")
+		assertEqual(t, env["REF"], "$B")
+		assertEqual(t, env["DB_HOST"], "x")
+	})
+
+	t.Run("reports lines", func(t *testing.T) {
+		report, err := LoadReport(WithPaths("config.json"), WithFs(fs), WithFormat(FormatJSON),
+			WithSetter(func(string, string) error { return nil }))
+		assertNoError(t, err)
+		for _, kr := range report.Loaded {
+			if kr.Key == "PORT" {
+				assertEqual(t, kr.Line, 3)
+			}
+		}
+	})
+
+	t.Run("syntax errors", func(t *testing.T) {
+		_, err := ParseString("{\n  \"A\": 1,\n  \"B\" 2\n}", WithFormat(FormatJSON))
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("expected *ParseError, got %v", err)
+		}
+		assertEqual(t, perr.Line, 3)
+
+		_, err = ParseString(`["A"]`, WithFormat(FormatJSON))
+		if !errors.As(err, &perr) {
+			t.Fatalf("expected *ParseError, got %v", err)
+		}
+		assertEqual(t, perr.Reason, "expected a JSON object")
+
+		for _, content := range []string{`{"A":1}xx`, `{"A":1}{}`, "{\"A\":1}\n\"B\""} {
+			_, err = ParseString(content, WithFormat(FormatJSON))
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected *ParseError for %q, got %v", content, err)
+			}
+		}
+		_, err = ParseString("{\"A\":1}\n\n", WithFormat(FormatJSON))
+		assertNoError(t, err)
+	})
+
+	t.Run("nested keys are upper snake case", func(t *testing.T) {
+		env, err := ParseString(`{"b":{"C":1,"d":{"e-f":2}},"list":["x"],"plain.key":"v"}`, WithFormat(FormatJSON))
+		assertNoError(t, err)
+		assertEqual(t, len(env), 4)
+		assertEqual(t, env["B_C"], "1")
+		assertEqual(t, env["B_D_E_F"], "2")
+		assertEqual(t, env["LIST_0"], "x")
+		assertEqual(t, env["plain.key"], "v")
+	})
+}
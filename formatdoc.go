@@ -0,0 +1,209 @@
+package dotenv
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// FormatOptions selects what FormatDocument normalizes. The zero value
+// keeps the document as it is.
+type FormatOptions struct {
+	// SortKeys orders entries by key. Comment lines directly above an
+	// entry move with it; a header comment separated from the first entry
+	// by a blank line stays on top. Repeated keys keep their order, so the
+	// effective values don't change.
+	SortKeys bool
+	// NormalizeQuotes writes values bare when they need no quotes and in
+	// double quotes otherwise, falling back to the original quotes where
+	// changing them would change the value.
+	NormalizeQuotes bool
+	// TrimWhitespace removes indentation, trailing whitespace and spaces
+	// around '=', separates inline comments by a single space and collapses
+	// runs of blank lines.
+	TrimWhitespace bool
+	// AlignComments lines up the inline comments of consecutive entries.
+	AlignComments bool
+}
+
+// FormatDocument returns doc in dotenv format, normalized according to
+// opts. The document itself is not modified.
+func FormatDocument(doc *Document, opts FormatOptions) []byte {
+	lines := make([]fmtLine, len(doc.stmts))
+	for i, st := range doc.stmts {
+		lines[i] = formatStatement(st, opts)
+	}
+	if opts.SortKeys {
+		lines = sortLines(lines)
+	}
+	if opts.TrimWhitespace {
+		lines = collapseBlank(lines)
+	}
+	if opts.AlignComments {
+		alignComments(lines)
+	}
+
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(l.code)
+		b.WriteString(l.comment)
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
+
+// fmtLine is a statement being formatted.
+type fmtLine struct {
+	key string
+	// code is the statement without its inline comment.
+	code    string
+	comment string
+	// blank is set for empty lines; comment lines and malformed lines are
+	// neither blank nor keyed.
+	blank bool
+}
+
+func formatStatement(st statement, opts FormatOptions) fmtLine {
+	l := fmtLine{key: st.key, code: st.raw, blank: strings.TrimSpace(st.raw) == ""}
+	// Keys without a value, such as Docker keys inherited from the
+	// environment, are kept like comment lines.
+	if st.key == "" || st.valueCol == 0 {
+		if opts.TrimWhitespace {
+			l.code = strings.TrimSpace(st.raw)
+		}
+		return l
+	}
+
+	value := st.raw[st.valueCol-1 : len(st.raw)-len(st.comment)]
+	l.code = st.raw[:st.valueCol-1]
+	l.comment = st.comment
+	if opts.NormalizeQuotes {
+		value = normalizeQuotes(st, value)
+	}
+	if opts.TrimWhitespace {
+		prefix := strings.TrimSpace(st.prefix)
+		if prefix != "" {
+			prefix += " "
+		}
+		l.code = prefix + st.writtenKey() + "="
+		if st.quote == 0 {
+			value = strings.TrimRight(value, " \t")
+		}
+		if l.comment != "" {
+			l.comment = " " + strings.TrimLeft(l.comment, " \t")
+		}
+	}
+	l.code += value
+	return l
+}
+
+// normalizeQuotes returns the canonical spelling of the value of st, whose
+// raw text is value, or value itself where a change would alter what the
+// parser reads in any mode.
+func normalizeQuotes(st statement, value string) string {
+	v := st.value
+	if strings.Contains(v, "\n") || st.quote == '"' && strings.ContainsAny(value, `\$`+"`") {
+		return value
+	}
+	switch {
+	case v == "":
+		// Quotes distinguish KEY="" from KEY=, see EmptyValues.
+		if st.quote != 0 {
+			return `""`
+		}
+		return v
+	case !strings.ContainsAny(v, " \t#'\"\\$`"):
+		return v
+	case !strings.ContainsAny(v, `"\`+"`") && (st.quote != '\'' || !strings.Contains(v, "$")):
+		return `"` + v + `"`
+	case st.quote == 0 && !strings.ContainsAny(v, "'$"):
+		return "'" + v + "'"
+	}
+	return value
+}
+
+// sortLines orders the entries of lines by key, moving comment lines with
+// the entry below them. Blank lines between entries are dropped.
+func sortLines(lines []fmtLine) []fmtLine {
+	// The header ends at the last blank line before the first entry.
+	first := slices.IndexFunc(lines, func(l fmtLine) bool { return l.key != "" })
+	if first < 0 {
+		return lines
+	}
+	header := 0
+	for i := first - 1; i >= 0; i-- {
+		if lines[i].blank {
+			header = i + 1
+			break
+		}
+	}
+
+	type block struct {
+		key   string
+		lines []fmtLine
+	}
+	var (
+		blocks  []block
+		pending []fmtLine
+	)
+	for _, l := range lines[header:] {
+		switch {
+		case l.blank:
+		case l.key == "":
+			pending = append(pending, l)
+		default:
+			blocks = append(blocks, block{key: l.key, lines: append(pending, l)})
+			pending = nil
+		}
+	}
+	slices.SortStableFunc(blocks, func(a, b block) int { return cmp.Compare(a.key, b.key) })
+
+	sorted := slices.Clone(lines[:header])
+	for _, b := range blocks {
+		sorted = append(sorted, b.lines...)
+	}
+	if len(pending) > 0 {
+		sorted = append(sorted, fmtLine{blank: true})
+		sorted = append(sorted, pending...)
+	}
+	return sorted
+}
+
+// collapseBlank drops leading and trailing blank lines and repeated blank
+// lines in between.
+func collapseBlank(lines []fmtLine) []fmtLine {
+	var out []fmtLine
+	for _, l := range lines {
+		if l.blank && (len(out) == 0 || out[len(out)-1].blank) {
+			continue
+		}
+		out = append(out, l)
+	}
+	for len(out) > 0 && out[len(out)-1].blank {
+		out = out[:len(out)-1]
+	}
+	return out
+}
+
+// alignComments pads the code of consecutive entries with inline comments
+// so that the comments start in the same column.
+func alignComments(lines []fmtLine) {
+	aligned := func(l fmtLine) bool {
+		return l.key != "" && l.comment != "" && !strings.Contains(l.code, "\n")
+	}
+	for i := 0; i < len(lines); {
+		if !aligned(lines[i]) {
+			i++
+			continue
+		}
+		j, width := i, 0
+		for ; j < len(lines) && aligned(lines[j]); j++ {
+			width = max(width, len(lines[j].code))
+		}
+		for k := i; k < j; k++ {
+			l := &lines[k]
+			l.comment = strings.Repeat(" ", width-len(l.code)+1) + strings.TrimLeft(l.comment, " \t")
+		}
+		i = j
+	}
+}
@@ -0,0 +1,122 @@
+package dotenv
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_formatDocument(t *testing.T) {
+	const src = `# app config
+
+  export   PORT = 8080   # http
+HOST='localhost'
+EMPTY=''
+PLAIN="hello world"
+
+
+# credentials
+TOKEN=abc$REF # expands
+ODD=a"b
+ESC="line\n"
+PASS='p$ss'
+`
+	format := func(t *testing.T, opts FormatOptions) string {
+		t.Helper()
+		doc, err := ParseDocument(strings.NewReader(src))
+		assertNoError(t, err)
+		return string(FormatDocument(doc, opts))
+	}
+
+	t.Run("zero options keep the document", func(t *testing.T) {
+		assertEqual(t, format(t, FormatOptions{}), src)
+	})
+
+	t.Run("trim whitespace", func(t *testing.T) {
+		assertEqual(t, format(t, FormatOptions{TrimWhitespace: true}), `# app config
+
+export PORT=8080 # http
+HOST='localhost'
+EMPTY=''
+PLAIN="hello world"
+
+# credentials
+TOKEN=abc$REF # expands
+ODD=a"b
+ESC="line\n"
+PASS='p$ss'
+`)
+	})
+
+	t.Run("normalize quotes", func(t *testing.T) {
+		got := format(t, FormatOptions{NormalizeQuotes: true, TrimWhitespace: true})
+		for _, want := range []string{
+			"\nHOST=localhost\n",
+			"\nEMPTY=\"\"\n",
+			"\nPLAIN=\"hello world\"\n",
+			"\nTOKEN=\"abc$REF\" # expands\n",
+			"\nODD='a\"b'\n",
+			"\nESC=\"line\\n\"\n",
+			"\nPASS='p$ss'\n",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("missing %q in\n%s", want, got)
+			}
+		}
+	})
+
+	t.Run("sort keys and align comments", func(t *testing.T) {
+		assertEqual(t, format(t, FormatOptions{SortKeys: true, TrimWhitespace: true, AlignComments: true}), `# app config
+
+EMPTY=''
+ESC="line\n"
+HOST='localhost'
+ODD=a"b
+PASS='p$ss'
+PLAIN="hello world"
+export PORT=8080 # http
+# credentials
+TOKEN=abc$REF # expands
+`)
+	})
+
+	t.Run("align comments", func(t *testing.T) {
+		doc, err := ParseDocument(strings.NewReader("A=1 # one\nLONGER=2  # two\nB=3\nC=4 # four\n"))
+		assertNoError(t, err)
+		assertEqual(t, string(FormatDocument(doc, FormatOptions{AlignComments: true})),
+			"A=1      # one\nLONGER=2 # two\nB=3\nC=4 # four\n")
+	})
+
+	t.Run("values are preserved", func(t *testing.T) {
+		want, err := ParseString(src)
+		assertNoError(t, err)
+		got, err := ParseString(format(t, FormatOptions{SortKeys: true, NormalizeQuotes: true, TrimWhitespace: true, AlignComments: true}))
+		assertNoError(t, err)
+		assertEqual(t, len(got), len(want))
+		for k, v := range want {
+			assertEqual(t, got[k], v)
+		}
+	})
+}
+
+func Test_formatEditedDocument(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader("A=1 # one\n"))
+	assertNoError(t, err)
+	assertNoError(t, doc.Set("NEW", "two words"))
+	assertNoError(t, doc.Rename("A", "LONGER"))
+	assertEqual(t, string(FormatDocument(doc, FormatOptions{NormalizeQuotes: true, TrimWhitespace: true})),
+		"LONGER=1 # one\nNEW=\"two words\"\n")
+}
+
+func Test_formatDocumentInheritedKeys(t *testing.T) {
+	t.Setenv("FMT_INHERITED", "from-env")
+	doc, err := ParseDocument(strings.NewReader("B=2\nFMT_INHERITED\nA=1\n"), WithDialect(DialectDocker))
+	assertNoError(t, err)
+	got := FormatDocument(doc, FormatOptions{SortKeys: true, NormalizeQuotes: true, TrimWhitespace: true, AlignComments: true})
+	assertEqual(t, string(got), "A=1\nB=2\nFMT_INHERITED\n")
+}
+
+func Test_formatDocumentSections(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader("[db]\nhost = x\n"), WithDialect(DialectINI))
+	assertNoError(t, err)
+	assertEqual(t, string(FormatDocument(doc, FormatOptions{TrimWhitespace: true})), "[db]\nhost=x\n")
+}
@@ -0,0 +1,85 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// GCPSecret describes a secret listed by a GCPSecretClient.
+type GCPSecret struct {
+	ID     string
+	Labels map[string]string
+}
+
+// GCPSecretClient is the part of the Google Cloud Secret Manager API used by
+// GCPSecretProvider. AccessSecretVersion receives a full resource name such
+// as projects/p/secrets/s/versions/latest and returns the payload. The
+// package does not depend on the Google Cloud SDK; adapt its client with a
+// few lines as shown for SSMClient.
+type GCPSecretClient interface {
+	AccessSecretVersion(ctx context.Context, name string) (string, error)
+	ListSecrets(ctx context.Context, project string) ([]GCPSecret, error)
+}
+
+// GCPSecretProvider reads secrets from Google Cloud Secret Manager, either
+// the ones named in Secrets or, when Labels is set, every secret of the
+// project carrying all of those labels. Secret IDs become keys through
+// NormalizeUpperSnake, so db-password is exported as DB_PASSWORD.
+type GCPSecretProvider struct {
+	Client  GCPSecretClient
+	Project string
+	Secrets []string
+	Labels  map[string]string
+	// Version selects the secret version, "latest" if empty.
+	Version string
+}
+
+// Fetch reads the selected secrets.
+func (p *GCPSecretProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	if p.Client == nil {
+		return nil, errors.New("gcp secret manager: client is required")
+	}
+	ids := p.Secrets
+	if len(p.Labels) > 0 {
+		secrets, err := p.Client.ListSecrets(ctx, p.Project)
+		if err != nil {
+			return nil, fmt.Errorf("gcp secret manager: list secrets of %s: %w", p.Project, err)
+		}
+		for _, s := range secrets {
+			if hasLabels(s.Labels, p.Labels) {
+				ids = append(ids[:len(ids):len(ids)], s.ID)
+			}
+		}
+	}
+
+	version := p.Version
+	if version == "" {
+		version = "latest"
+	}
+	values := make(map[string]string, len(ids))
+	for _, id := range ids {
+		name := fmt.Sprintf("projects/%s/secrets/%s/versions/%s", p.Project, id, version)
+		v, err := p.Client.AccessSecretVersion(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("gcp secret manager: %s: %w", name, err)
+		}
+		values[NormalizeUpperSnake(id)] = v
+	}
+	return values, nil
+}
+
+// String returns a gcpsecrets:// URL naming the project.
+func (p *GCPSecretProvider) String() string {
+	return "gcpsecrets://" + p.Project
+}
+
+// hasLabels reports whether labels contains every pair of want.
+func hasLabels(labels, want map[string]string) bool {
+	for k, v := range want {
+		if got, ok := labels[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
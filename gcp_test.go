@@ -0,0 +1,35 @@
+package dotenv
+
+import (
+	"context"
+	"testing"
+)
+
+type fakeGCPSecrets map[string]string
+
+func (f fakeGCPSecrets) AccessSecretVersion(_ context.Context, name string) (string, error) {
+	return f[name], nil
+}
+
+func (f fakeGCPSecrets) ListSecrets(context.Context, string) ([]GCPSecret, error) {
+	return []GCPSecret{
+		{ID: "db-password", Labels: map[string]string{"app": "api", "env": "prod"}},
+		{ID: "other", Labels: map[string]string{"app": "web"}},
+	}, nil
+}
+
+func Test_gcpSecretProvider(t *testing.T) {
+	client := fakeGCPSecrets{
+		"projects/p/secrets/db-password/versions/latest": "s3cret",
+		"projects/p/secrets/api-token/versions/3":        "t3",
+	}
+
+	env, err := (&GCPSecretProvider{Client: client, Project: "p", Labels: map[string]string{"app": "api"}}).Fetch(context.Background())
+	assertNoError(t, err)
+	assertEqual(t, len(env), 1)
+	assertEqual(t, env["DB_PASSWORD"], "s3cret")
+
+	env, err = (&GCPSecretProvider{Client: client, Project: "p", Secrets: []string{"api-token"}, Version: "3"}).Fetch(context.Background())
+	assertNoError(t, err)
+	assertEqual(t, env["API_TOKEN"], "t3")
+}
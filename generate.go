@@ -0,0 +1,93 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Variable describes a variable declared by a struct field for Unmarshal.
+type Variable struct {
+	// Key is the variable name, including the prefixes of nested structs.
+	Key string
+	// Type is the Go type of the field, "duration" for time.Duration.
+	Type string
+	// Default is the `default` tag of the field.
+	Default string
+	// Description is the `desc` tag of the field.
+	Description string
+}
+
+// Variables lists the variables declared by the `env` tags of the struct
+// type of v, which may also be a pointer to a struct, in field order. The
+// fields are walked like Unmarshal walks them; `default` and `desc` tags
+// fill in Default and Description.
+func Variables(v any) ([]Variable, error) {
+	rt := reflect.TypeOf(v)
+	if rt != nil && rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil, errors.New("variables source should be a struct or a pointer to a struct")
+	}
+	var vars []Variable
+	for _, f := range envFields(rt) {
+		typ := f.field.Type.String()
+		if f.field.Type == durationType {
+			typ = "duration"
+		}
+		vars = append(vars, Variable{
+			Key:         f.key,
+			Type:        typ,
+			Default:     f.field.Tag.Get("default"),
+			Description: f.field.Tag.Get("desc"),
+		})
+	}
+	return vars, nil
+}
+
+// GenerateExample returns a .env.example for the variables of v, see
+// Variables: each variable set to its default, below its description as a
+// comment.
+func GenerateExample(v any) ([]byte, error) {
+	vars, err := Variables(v)
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	for i, va := range vars {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		for line := range strings.Lines(va.Description) {
+			b.WriteString(strings.TrimRight("# "+strings.TrimRight(line, "\n"), " "))
+			b.WriteByte('\n')
+		}
+		b.WriteString(va.Key + "=" + quoteValue(va.Default) + "\n")
+	}
+	return []byte(b.String()), nil
+}
+
+// GenerateMarkdown returns a Markdown table documenting the variables of v,
+// see Variables, for READMEs and onboarding docs.
+func GenerateMarkdown(v any) ([]byte, error) {
+	vars, err := Variables(v)
+	if err != nil {
+		return nil, err
+	}
+	cell := strings.NewReplacer("|", `\|`, "\n", " ")
+	code := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		return "`" + cell.Replace(s) + "`"
+	}
+	var b strings.Builder
+	b.WriteString("| Variable | Type | Default | Description |\n")
+	b.WriteString("| --- | --- | --- | --- |\n")
+	for _, va := range vars {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", code(va.Key), va.Type, code(va.Default), cell.Replace(va.Description))
+	}
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,68 @@
+package dotenv
+
+import (
+	"testing"
+	"time"
+)
+
+type generateConfig struct {
+	Port    int           `env:"PORT" default:"8080" desc:"Port the server listens on."`
+	Timeout time.Duration `env:"TIMEOUT" default:"30s" desc:"Request timeout."`
+	DB      struct {
+		URL string `env:"URL" desc:"Connection string | DSN.\nRequired in production."`
+	} `env:"DB"`
+	Greeting string `env:"GREETING" default:"hello world"`
+	Skipped  string `env:"-"`
+	Internal struct {
+		Field string `env:"FIELD"`
+	} `env:"-"`
+	untagged string
+}
+
+func Test_variables(t *testing.T) {
+	vars, err := Variables(&generateConfig{})
+	assertNoError(t, err)
+	want := []Variable{
+		{Key: "PORT", Type: "int", Default: "8080", Description: "Port the server listens on."},
+		{Key: "TIMEOUT", Type: "duration", Default: "30s", Description: "Request timeout."},
+		{Key: "DB_URL", Type: "string", Description: "Connection string | DSN.\nRequired in production."},
+		{Key: "GREETING", Type: "string", Default: "hello world"},
+	}
+	assertEqual(t, len(vars), len(want))
+	for i := range want {
+		assertEqual(t, vars[i], want[i])
+	}
+
+	_, err = Variables("not a struct")
+	if err == nil {
+		t.Fatal("expected error for non-struct value")
+	}
+}
+
+func Test_generateExample(t *testing.T) {
+	got, err := GenerateExample(generateConfig{})
+	assertNoError(t, err)
+	assertEqual(t, string(got), `# Port the server listens on.
+PORT=8080
+
+# Request timeout.
+TIMEOUT=30s
+
+# Connection string | DSN.
+# Required in production.
+DB_URL=
+
+GREETING='hello world'
+`)
+}
+
+func Test_generateMarkdown(t *testing.T) {
+	got, err := GenerateMarkdown((*generateConfig)(nil))
+	assertNoError(t, err)
+	assertEqual(t, string(got), "| Variable | Type | Default | Description |\n"+
+		"| --- | --- | --- | --- |\n"+
+		"| `PORT` | int | `8080` | Port the server listens on. |\n"+
+		"| `TIMEOUT` | duration | `30s` | Request timeout. |\n"+
+		"| `DB_URL` | string |  | Connection string \\| DSN. Required in production. |\n"+
+		"| `GREETING` | string | `hello world` |  |\n")
+}
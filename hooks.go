@@ -0,0 +1,60 @@
+package dotenv
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// ValueHook inspects a parsed pair before it is exported. It returns the
+// value to use, false to drop the key, or an error to fail loading.
+type ValueHook func(key, value string) (string, bool, error)
+
+// WithValueHook adds a hook called for every parsed pair, in key order, after
+// expansion and key selection and before required keys and schemas are
+// checked. Hooks run in the order they were added, each seeing the result of
+// the previous one. Typical uses are expanding "~", resolving relative paths
+// or decoding secrets.
+func WithValueHook(hook ValueHook) Option {
+	return func(o *Options) {
+		o.ValueHooks = append(o.ValueHooks, hook)
+	}
+}
+
+// transformValues resolves file indirections, decodes base64 values and
+// flattens json values. It runs before selectKeys so that the prefix and
+// allow/deny options apply to the keys these steps derive.
+func transformValues(opts Options, env entries) error {
+	if err := applyFileIndirection(opts, env); err != nil {
+		return err
+	}
+	if err := decodeBase64Values(opts, env); err != nil {
+		return err
+	}
+	return flattenJSONValues(opts, env)
+}
+
+func applyValueHooks(opts Options, env entries) error {
+	if len(opts.ValueHooks) == 0 {
+		return nil
+	}
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		e := env[key]
+		if e.unset {
+			continue
+		}
+		for _, hook := range opts.ValueHooks {
+			val, keep, err := hook(key, e.value)
+			if err != nil {
+				return fmt.Errorf("%s:%d: %s: %w", e.file, e.line, key, err)
+			}
+			if !keep {
+				delete(env, key)
+				break
+			}
+			e.value = val
+			env[key] = e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package dotenv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_valueHooks(t *testing.T) {
+	home := func(key, value string) (string, bool, error) {
+		if rest, ok := strings.CutPrefix(value, "~/"); ok {
+			return "/home/me/" + rest, true, nil
+		}
+		return value, true, nil
+	}
+	dropDebug := func(key, value string) (string, bool, error) {
+		return value, key != "DEBUG", nil
+	}
+
+	env, err := ParseString("DIR=~/data\nDEBUG=1\nNAME=x\n", WithValueHook(home), WithValueHook(dropDebug))
+	assertNoError(t, err)
+	assertEqual(t, len(env), 2)
+	assertEqual(t, env["DIR"], "/home/me/data")
+	assertEqual(t, env["NAME"], "x")
+
+	errBad := errors.New("bad value")
+	_, err = ParseString("A=1\nB=2\n", WithValueHook(func(key, value string) (string, bool, error) {
+		if key == "B" {
+			return "", false, errBad
+		}
+		return value, true, nil
+	}))
+	if !errors.Is(err, errBad) {
+		t.Fatalf("expected errBad, got %v", err)
+	}
+	assertEqual(t, err.Error(), "<string>:2: B: bad value")
+}
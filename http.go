@@ -0,0 +1,119 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+func init() {
+	for _, scheme := range []string{"http", "https"} {
+		RegisterProvider(scheme, openHTTPProvider)
+	}
+}
+
+var (
+	httpProvidersMu sync.Mutex
+	httpProviders   = map[string]*HTTPProvider{}
+)
+
+// openHTTPProvider returns the provider for a URL passed to WithPaths. It is
+// shared by every load of that URL, so that reloads can send conditional
+// requests.
+func openHTTPProvider(rawURL string) (Provider, error) {
+	httpProvidersMu.Lock()
+	defer httpProvidersMu.Unlock()
+	p, ok := httpProviders[rawURL]
+	if !ok {
+		p = &HTTPProvider{URL: rawURL}
+		httpProviders[rawURL] = p
+	}
+	return p, nil
+}
+
+// HTTPProvider fetches dotenv content from a URL. Responses carrying an ETag
+// are remembered and later fetches send If-None-Match, reusing the previous
+// values when the server answers 304 Not Modified. http and https URLs
+// passed to WithPaths use an HTTPProvider with default settings, one per URL
+// for the lifetime of the process, so Store reloads and ReloadOnSignal
+// benefit from the cache as well.
+type HTTPProvider struct {
+	URL string
+	// Header is added to every request, e.g. for authorization.
+	Header http.Header
+	// Client sends the requests, http.DefaultClient if nil.
+	Client *http.Client
+	// Timeout bounds each request when positive.
+	Timeout time.Duration
+	// Options configure how the body is parsed, e.g.
+	// WithFormat(FormatJSON) or WithMaxFileSize.
+	Options []Option
+
+	mu     sync.Mutex
+	etag   string
+	cached map[string]string
+}
+
+// Fetch requests the URL and parses the body.
+func (p *HTTPProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range p.Header {
+		req.Header[key] = values
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.etag != "" {
+		req.Header.Set("If-None-Match", p.etag)
+	}
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			uerr.URL = p.String()
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && p.cached != nil:
+		return maps.Clone(p.cached), nil
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	values, err := parseReader(ctx, resp.Body, p.String(), p.Options)
+	if err != nil {
+		return nil, err
+	}
+	p.etag, p.cached = resp.Header.Get("ETag"), nil
+	if p.etag != "" {
+		p.cached = maps.Clone(values)
+	}
+	return values, nil
+}
+
+// String returns the URL without userinfo and query, which often carry
+// credentials.
+func (p *HTTPProvider) String() string {
+	return redactURL(p.URL)
+}
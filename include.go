@@ -0,0 +1,76 @@
+package dotenv
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// WithIncludes enables include directives. A line of the form
+//
+//	#include ./shared.env
+//	source ./shared.env
+//
+// parses the named file in place, as if its content was pasted there. Paths
+// are resolved relative to the including file within the filesystem set by
+// WithFs (the working directory by default); ParseReader and ParseString
+// resolve them relative to the root of that filesystem and need WithFs.
+// Include cycles fail with a *ParseError. The systemd dialect does not
+// support includes.
+func WithIncludes() Option {
+	return func(o *Options) {
+		o.Includes = true
+	}
+}
+
+// includeDirective returns the target of an include line.
+func includeDirective(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	rest, ok := strings.CutPrefix(line, "#include")
+	if !ok {
+		rest, ok = strings.CutPrefix(line, "source")
+	}
+	if !ok || rest == "" || (rest[0] != ' ' && rest[0] != '\t') {
+		return "", false
+	}
+	target := strings.TrimSpace(rest)
+	if len(target) >= 2 && isQuote(target) && target[len(target)-1] == target[0] {
+		target = target[1 : len(target)-1]
+	}
+	return target, target != ""
+}
+
+// includeFile parses the file included by st into raws. stack holds the
+// files currently being parsed, outermost first.
+func includeFile(ctx context.Context, opts Options, from string, st statement, raws *[]rawEntry, stack []string) error {
+	target := path.Join(path.Dir(from), st.include)
+	errorAt := func(reason string) error {
+		return &ParseError{File: from, Line: st.line, Col: len(st.prefix) + 1, Reason: reason}
+	}
+	if i := slices.Index(stack, target); i >= 0 {
+		return errorAt("include cycle: " + strings.Join(append(stack[i:], target), " -> "))
+	}
+	if opts.RootFs == nil {
+		return errorAt(fmt.Sprintf("can't include %s without a filesystem, see WithFs", target))
+	}
+	if !fs.ValidPath(target) {
+		return errorAt(fmt.Sprintf("invalid include path %s", st.include))
+	}
+
+	if _, err := fs.Stat(opts.RootFs, target); err != nil {
+		return fmt.Errorf("%s:%d: include: %w", from, st.line, err)
+	}
+	return processFile(ctx, opts.RootFs, target, func(f fs.File) error {
+		if err := checkPermissions(opts, f, target); err != nil {
+			return err
+		}
+		r, verify := opts.lock.reader(f, target)
+		if err := parseFileStack(ctx, opts, r, target, raws, stack); err != nil {
+			return err
+		}
+		return verify()
+	})
+}
@@ -0,0 +1,69 @@
+package dotenv
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_includes(t *testing.T) {
+	t.Run("includes relative to the including file", func(t *testing.T) {
+		fs := fstest.MapFS{
+			"svc/.env":         &fstest.MapFile{Data: []byte("A=1\n#include ../shared/base.env\nB=${SHARED}-b\n")},
+			"shared/base.env":  &fstest.MapFile{Data: []byte("SHARED=s\nA=from-shared\nsource 'extra.env'\n")},
+			"shared/extra.env": &fstest.MapFile{Data: []byte("EXTRA=e\n")},
+		}
+		env, err := Parse(WithPaths("svc"), WithFs(fs), WithIncludes(), WithExpand(true))
+		assertNoError(t, err)
+		assertEqual(t, env["A"], "from-shared")
+		assertEqual(t, env["B"], "s-b")
+		assertEqual(t, env["EXTRA"], "e")
+	})
+
+	t.Run("directives are comments unless enabled", func(t *testing.T) {
+		fs := fstest.MapFS{
+			".env":      &fstest.MapFile{Data: []byte("#include other.env\nA=1\n")},
+			"other.env": &fstest.MapFile{Data: []byte("B=2\n")},
+		}
+		env, err := Parse(WithFs(fs), WithStrict())
+		assertNoError(t, err)
+		assertEqual(t, len(env), 1)
+	})
+
+	t.Run("cycles are reported", func(t *testing.T) {
+		fs := fstest.MapFS{
+			".env":  &fstest.MapFile{Data: []byte("#include a.env\n")},
+			"a.env": &fstest.MapFile{Data: []byte("A=1\n#include b.env\n")},
+			"b.env": &fstest.MapFile{Data: []byte("#include a.env\n")},
+		}
+		_, err := Parse(WithFs(fs), WithIncludes())
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("expected *ParseError, got %v", err)
+		}
+		assertEqual(t, perr.File, "b.env")
+		assertEqual(t, perr.Reason, "include cycle: a.env -> b.env -> a.env")
+	})
+
+	t.Run("missing include", func(t *testing.T) {
+		fsys := fstest.MapFS{".env": &fstest.MapFile{Data: []byte("#include nope.env\n")}}
+		_, err := Parse(WithFs(fsys), WithIncludes())
+		if !errors.Is(err, fs.ErrNotExist) || !strings.HasPrefix(err.Error(), ".env:1: include") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("parse string needs a filesystem", func(t *testing.T) {
+		_, err := ParseString("#include x.env\n", WithIncludes())
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		fsys := fstest.MapFS{"x.env": &fstest.MapFile{Data: []byte("X=1\n")}}
+		env, err := ParseString("#include x.env\n", WithIncludes(), WithFs(fsys))
+		assertNoError(t, err)
+		assertEqual(t, env["X"], "1")
+	})
+}
@@ -0,0 +1,47 @@
+package dotenv
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// WithFileIndirection enables the Docker secrets convention: a key ending in
+// _FILE names a file whose content, with surrounding whitespace trimmed,
+// becomes the value of the key without the suffix. FOO_FILE=/run/secrets/foo
+// sets FOO and drops FOO_FILE. Paths are opened with os.ReadFile, not the
+// filesystem given to WithFs, so secrets mounted outside the project are
+// reachable. Defining both FOO and FOO_FILE is an error. Indirection is
+// resolved before key selection, so WithAllowKeys, WithDenyKeys and the
+// prefix options refer to FOO rather than FOO_FILE.
+func WithFileIndirection() Option {
+	return func(o *Options) {
+		o.FileIndirection = true
+	}
+}
+
+func applyFileIndirection(opts Options, env entries) error {
+	if !opts.FileIndirection {
+		return nil
+	}
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		name, ok := strings.CutSuffix(key, "_FILE")
+		e := env[key]
+		if !ok || name == "" || e.unset {
+			continue
+		}
+		if _, ok := env.lookup(name); ok {
+			return fmt.Errorf("%s:%d: both %s and %s are set", e.file, e.line, name, key)
+		}
+		data, err := os.ReadFile(e.value)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %s: %w", e.file, e.line, key, err)
+		}
+		delete(env, key)
+		e.value = strings.TrimSpace(string(data))
+		env[name] = e
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package dotenv
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_fileIndirection(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "db_password")
+	if err := os.WriteFile(secret, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := ParseString("DB_PASSWORD_FILE="+secret+"\nDB_USER=app\n", WithFileIndirection())
+	assertNoError(t, err)
+	assertEqual(t, len(env), 2)
+	assertEqual(t, env["DB_PASSWORD"], "s3cret")
+
+	env, err = ParseString("DB_PASSWORD_FILE=" + secret + "\n")
+	assertNoError(t, err)
+	assertEqual(t, env["DB_PASSWORD_FILE"], secret)
+
+	_, err = ParseString("DB_PASSWORD=x\nDB_PASSWORD_FILE="+secret+"\n", WithFileIndirection())
+	if err == nil {
+		t.Fatal("expected error for both keys set")
+	}
+
+	_, err = ParseString("TOKEN_FILE=/does/not/exist\n", WithFileIndirection())
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected fs.ErrNotExist, got %v", err)
+	}
+}
+
+func Test_fileIndirectionKeySelection(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "pw")
+	if err := os.WriteFile(secret, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	content := "PW_FILE=" + secret + "\nUSER=app\n"
+
+	env, err := ParseString(content, WithFileIndirection(), WithDenyKeys("PW"))
+	assertNoError(t, err)
+	assertEqual(t, len(env), 1)
+	assertEqual(t, env["USER"], "app")
+
+	env, err = ParseString(content, WithFileIndirection(), WithAllowKeys("PW"))
+	assertNoError(t, err)
+	assertEqual(t, len(env), 1)
+	assertEqual(t, env["PW"], "s3cret")
+
+	env, err = ParseString(content, WithFileIndirection(), WithOnlyPrefix("USER"))
+	assertNoError(t, err)
+	assertEqual(t, len(env), 1)
+	assertEqual(t, env["USER"], "app")
+
+	env, err = ParseString(content, WithFileIndirection(), WithPrefix("APP_"))
+	assertNoError(t, err)
+	assertEqual(t, env["APP_PW"], "s3cret")
+}
@@ -0,0 +1,82 @@
+package dotenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// WithJSONValues flattens values written as "json:<document>" into one key
+// per leaf, joining nested names with '_' after NormalizeUpperSnake:
+//
+//	DB=json:{"host":"x","port":5432,"replicas":["a","b"]}
+//
+// yields DB_HOST=x, DB_PORT=5432, DB_REPLICAS_0=a and DB_REPLICAS_1=b; DB
+// itself is dropped. Keys defined explicitly take precedence over flattened
+// ones. Null becomes an empty string. Invalid JSON is an error. Flattening
+// happens before key selection, so WithAllowKeys, WithDenyKeys and the prefix
+// options refer to the flattened keys.
+func WithJSONValues() Option {
+	return func(o *Options) {
+		o.JSONValues = true
+	}
+}
+
+func flattenJSONValues(opts Options, env entries) error {
+	if !opts.JSONValues {
+		return nil
+	}
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		e := env[key]
+		doc, ok := strings.CutPrefix(e.value, "json:")
+		if !ok || e.unset {
+			continue
+		}
+		dec := json.NewDecoder(strings.NewReader(doc))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return fmt.Errorf("%s:%d: %s: invalid json value: %w", e.file, e.line, key, err)
+		}
+		if _, err := dec.Token(); err != io.EOF {
+			return fmt.Errorf("%s:%d: %s: invalid json value: trailing data", e.file, e.line, key)
+		}
+
+		delete(env, key)
+		flattenJSON(key, v, func(name, value string) {
+			if _, ok := env[name]; ok {
+				return
+			}
+			leaf := e
+			leaf.value = value
+			env[name] = leaf
+		})
+	}
+	return nil
+}
+
+// flattenJSON calls set for every leaf of v with its key joined to prefix.
+func flattenJSON(prefix string, v any, set func(key, value string)) {
+	switch v := v.(type) {
+	case map[string]any:
+		for name, child := range v {
+			flattenJSON(prefix+"_"+NormalizeUpperSnake(name), child, set)
+		}
+	case []any:
+		for i, child := range v {
+			flattenJSON(prefix+"_"+strconv.Itoa(i), child, set)
+		}
+	case string:
+		set(prefix, v)
+	case json.Number:
+		set(prefix, v.String())
+	case bool:
+		set(prefix, strconv.FormatBool(v))
+	case nil:
+		set(prefix, "")
+	}
+}
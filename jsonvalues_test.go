@@ -0,0 +1,52 @@
+package dotenv
+
+import "testing"
+
+func Test_jsonValues(t *testing.T) {
+	const content = `DB=json:{"host":"x","port":5432,"tls":true,"replicas":["a","b"],"opts":{"pool-size":null}}
+DB_PORT=6543
+`
+	env, err := ParseString(content, WithJSONValues())
+	assertNoError(t, err)
+	assertEqual(t, len(env), 6)
+	assertEqual(t, env["DB_HOST"], "x")
+	assertEqual(t, env["DB_PORT"], "6543")
+	assertEqual(t, env["DB_TLS"], "true")
+	assertEqual(t, env["DB_REPLICAS_0"], "a")
+	assertEqual(t, env["DB_REPLICAS_1"], "b")
+	assertEqual(t, env["DB_OPTS_POOL_SIZE"], "")
+	_, ok := env["DB"]
+	assertEqual(t, ok, false)
+
+	for _, content := range []string{`DB=json:{"host":`, `DB=json:{"host":"x"}}`, `DB=json:{"host":"x"}]`, `DB=json:{"host":"x"} 1`} {
+		_, err = ParseString(content+"\n", WithJSONValues())
+		if err == nil {
+			t.Fatalf("expected error for invalid json in %q", content)
+		}
+	}
+	_, err = ParseString(`DB='json:{"host":"x"}  '`+"\n", WithJSONValues())
+	assertNoError(t, err)
+}
+
+func Test_jsonValuesKeySelection(t *testing.T) {
+	const content = `DB=json:{"host":"x","password":"p"}` + "\n"
+
+	env, err := ParseString(content, WithJSONValues(), WithDenyKeys("DB_PASSWORD"))
+	assertNoError(t, err)
+	assertEqual(t, len(env), 1)
+	assertEqual(t, env["DB_HOST"], "x")
+
+	env, err = ParseString(content, WithJSONValues(), WithAllowKeys("DB"))
+	assertNoError(t, err)
+	assertEqual(t, len(env), 0)
+
+	env, err = ParseString(content, WithJSONValues(), WithAllowKeys("DB_HOST"))
+	assertNoError(t, err)
+	assertEqual(t, len(env), 1)
+	assertEqual(t, env["DB_HOST"], "x")
+
+	env, err = ParseString("APP_"+content, WithJSONValues(), WithStripPrefix("APP_"), WithAllowKeys("DB_PASSWORD"))
+	assertNoError(t, err)
+	assertEqual(t, len(env), 1)
+	assertEqual(t, env["DB_PASSWORD"], "p")
+}
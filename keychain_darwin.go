@@ -0,0 +1,28 @@
+package dotenv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+func keychainLookup(ctx context.Context, service, account string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "security", "find-generic-password", "-s", service, "-a", account, "-w")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		// security exits with 44 when no matching item exists.
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 44 {
+			return "", ErrKeyNotFound
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", errors.New(msg)
+		}
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
@@ -0,0 +1,79 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// WithKeyDirs reads directories holding one file per key, as produced by
+// Kubernetes ConfigMap and Secret volume mounts: the file name is the key
+// and the content, minus one trailing line break, the value. The directories
+// are read after the dotenv paths, in order, and override them. Hidden
+// entries such as Kubernetes' "..data" are skipped. Relative directories are
+// opened in the filesystem set by WithFs, absolute ones in the OS
+// filesystem. Missing directories are logged and skipped unless
+// WithRequiredPaths is used.
+func WithKeyDirs(dirs ...string) Option {
+	return func(o *Options) {
+		o.KeyDirs = append(o.KeyDirs, dirs...)
+	}
+}
+
+// parseKeyDir appends an entry per regular file in dir to raws.
+func parseKeyDir(ctx context.Context, opts Options, dir string, raws *[]rawEntry) error {
+	fsys, root := opts.RootFs, path.Clean(dir)
+	if filepath.IsAbs(dir) {
+		fsys, root = os.DirFS(dir), "."
+	}
+
+	entries, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) && !opts.RequirePaths {
+			opts.Logger.Warn("key directory not found", "path", dir)
+			return nil
+		}
+		if errors.Is(err, fs.ErrNotExist) {
+			return &PathError{Path: dir}
+		}
+		return fmt.Errorf("read key directory %s: %w", dir, err)
+	}
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		name := path.Join(root, e.Name())
+		// Mounted files are usually symlinks; look at what they point to.
+		info, err := fs.Stat(fsys, name)
+		if err != nil {
+			return fmt.Errorf("stat %s: %w", path.Join(dir, e.Name()), err)
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", path.Join(dir, e.Name()), err)
+		}
+		key := e.Name()
+		if opts.KeyNormalizer != nil {
+			if key = opts.KeyNormalizer(key); key == "" {
+				continue
+			}
+		}
+		value := strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+		*raws = append(*raws, rawEntry{
+			statement: statement{line: 1, key: key, value: value, quote: '\''},
+			file:      path.Join(dir, e.Name()),
+		})
+	}
+	return nil
+}
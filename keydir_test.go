@@ -0,0 +1,51 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_keyDirs(t *testing.T) {
+	t.Run("overrides dotenv files", func(t *testing.T) {
+		fs := fstest.MapFS{
+			".env":               &fstest.MapFile{Data: []byte("A=from-env\nB=b\n")},
+			"config/A":           &fstest.MapFile{Data: []byte("from-mount\n")},
+			"config/CERT":        &fstest.MapFile{Data: []byte("line1\nline2\n")},
+			"config/..data/A":    &fstest.MapFile{Data: []byte("hidden")},
+			"config/.hidden":     &fstest.MapFile{Data: []byte("hidden")},
+			"config/nested/file": &fstest.MapFile{Data: []byte("x")},
+		}
+		report, err := LoadReport(WithFs(fs), WithKeyDirs("config", "missing"),
+			WithSetter(func(string, string) error { return nil }))
+		assertNoError(t, err)
+		assertEqual(t, len(report.Loaded), 3)
+
+		env, err := Parse(WithFs(fs), WithKeyDirs("config"))
+		assertNoError(t, err)
+		assertEqual(t, len(env), 3)
+		assertEqual(t, env["A"], "from-mount")
+		assertEqual(t, env["B"], "b")
+		assertEqual(t, env["CERT"], "line1\nline2")
+
+		_, err = Parse(WithFs(fs), WithKeyDirs("missing"), WithRequiredPaths())
+		if err == nil {
+			t.Fatal("expected error for missing key directory")
+		}
+	})
+
+	t.Run("absolute directories with kubernetes symlinks", func(t *testing.T) {
+		dir := t.TempDir()
+		data := filepath.Join(dir, "..2026_01_01")
+		assertNoError(t, os.Mkdir(data, 0o755))
+		assertNoError(t, os.WriteFile(filepath.Join(data, "db.password"), []byte("s3cret"), 0o600))
+		assertNoError(t, os.Symlink("..2026_01_01", filepath.Join(dir, "..data")))
+		assertNoError(t, os.Symlink(filepath.Join("..data", "db.password"), filepath.Join(dir, "db.password")))
+
+		env, err := Parse(WithFs(fstest.MapFS{}), WithKeyDirs(dir), WithKeyNormalizer(NormalizeUpperSnake))
+		assertNoError(t, err)
+		assertEqual(t, len(env), 1)
+		assertEqual(t, env["DB_PASSWORD"], "s3cret")
+	})
+}
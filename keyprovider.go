@@ -0,0 +1,117 @@
+package dotenv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ErrKeyNotFound is returned by a KeyProvider that has no key of the
+// requested name.
+var ErrKeyNotFound = errors.New("key not found")
+
+// KeyProvider looks up the keys used by the encryption features, such as
+// DOTENV_PRIVATE_KEY_PRODUCTION for WithDecryption or DOTENV_KEY for
+// WithVault, by the name of their environment variable. It returns
+// ErrKeyNotFound for keys it doesn't have.
+type KeyProvider interface {
+	Key(ctx context.Context, name string) (string, error)
+}
+
+// KeyProviderFunc adapts a function to the KeyProvider interface.
+type KeyProviderFunc func(ctx context.Context, name string) (string, error)
+
+// Key calls f(ctx, name).
+func (f KeyProviderFunc) Key(ctx context.Context, name string) (string, error) {
+	return f(ctx, name)
+}
+
+// WithKeyProvider looks up encryption keys that are not set in the
+// environment with p, so that they don't have to live in plaintext on disk
+// or in the environment:
+//
+//	dotenv.Load(dotenv.WithDecryption(), dotenv.WithKeyProvider(dotenv.Keychain{}))
+func WithKeyProvider(p KeyProvider) Option {
+	return func(o *Options) {
+		o.KeyProvider = p
+	}
+}
+
+// Keychain is a KeyProvider backed by the credential store of the operating
+// system: the login Keychain on macOS, the Credential Manager on Windows and
+// the Secret Service (GNOME Keyring, KWallet) elsewhere, through the
+// secret-tool command.
+//
+// Keys are stored as generic passwords with Service as the service and the
+// key name as the account. On macOS, for example:
+//
+//	security add-generic-password -s dotenv -a DOTENV_PRIVATE_KEY -w <key>
+//
+// Windows credentials are named service:name, and Secret Service items carry
+// the attributes service and account. Windows credential blobs may hold the
+// key as UTF-16LE, as written by cmdkey and the Credential Manager, or as
+// UTF-8.
+type Keychain struct {
+	// Service groups the keys, "dotenv" if empty.
+	Service string
+}
+
+// Key reads the key stored for name.
+func (k Keychain) Key(ctx context.Context, name string) (string, error) {
+	service := k.Service
+	if service == "" {
+		service = "dotenv"
+	}
+	key, err := keychainLookup(ctx, service, name)
+	if err != nil && !errors.Is(err, ErrKeyNotFound) {
+		return "", fmt.Errorf("keychain %s/%s: %w", service, name, err)
+	}
+	return key, err
+}
+
+// credentialBlob decodes the secret of a Windows credential. Blobs that are
+// valid UTF-8 without control characters are taken as is. Otherwise a blob
+// of even length that decodes cleanly as UTF-16LE is decoded: UTF-16 text
+// has NUL or other control bytes for ASCII and most scripts, so printable
+// keys can't be mistaken for it. A trailing NUL terminator is dropped.
+func credentialBlob(blob []byte) string {
+	if utf8.Valid(blob) && bytes.IndexFunc(blob, unicode.IsControl) < 0 {
+		return string(blob)
+	}
+	if len(blob)%2 != 0 {
+		return string(blob)
+	}
+	units := make([]uint16, len(blob)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(blob[2*i:])
+	}
+	runes := utf16.Decode(units)
+	if !slices.Equal(utf16.Encode(runes), units) {
+		return string(blob)
+	}
+	return strings.TrimSuffix(string(runes), "\x00")
+}
+
+// key returns the key named name from the environment or the key provider,
+// or "" when neither has it.
+func (o Options) key(ctx context.Context, name string) (string, error) {
+	if key := os.Getenv(name); key != "" {
+		return key, nil
+	}
+	if o.KeyProvider == nil {
+		return "", nil
+	}
+	key, err := o.KeyProvider.Key(ctx, name)
+	if errors.Is(err, ErrKeyNotFound) {
+		return "", nil
+	}
+	return key, err
+}
@@ -0,0 +1,97 @@
+package dotenv
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"unicode/utf16"
+)
+
+func Test_keyProvider(t *testing.T) {
+	pub, priv, err := GenerateKeyPair()
+	assertNoError(t, err)
+	token, _ := Encrypt("tok", pub)
+	vaultKey, vault := sealVault(t, "ci", "FROM=vault\n")
+	fs := fstest.MapFS{
+		".env":       &fstest.MapFile{Data: []byte("TOKEN=" + token + "\n")},
+		".env.vault": &fstest.MapFile{Data: []byte("DOTENV_VAULT_CI=" + vault + "\n")},
+	}
+	t.Setenv("DOTENV_PRIVATE_KEY", "")
+	t.Setenv("DOTENV_KEY", "")
+
+	var asked []string
+	keys := KeyProviderFunc(func(_ context.Context, name string) (string, error) {
+		asked = append(asked, name)
+		switch name {
+		case "DOTENV_PRIVATE_KEY":
+			return priv, nil
+		case "DOTENV_KEY":
+			return vaultKey, nil
+		}
+		return "", ErrKeyNotFound
+	})
+
+	t.Run("private keys", func(t *testing.T) {
+		env, err := Parse(WithFs(fs), WithDecryption(), WithKeyProvider(keys))
+		assertNoError(t, err)
+		assertEqual(t, env["TOKEN"], "tok")
+	})
+
+	t.Run("environment wins", func(t *testing.T) {
+		_, other, _ := GenerateKeyPair()
+		t.Setenv("DOTENV_PRIVATE_KEY", other)
+		_, err := Parse(WithFs(fs), WithDecryption(), WithKeyProvider(keys))
+		if err == nil || !strings.Contains(err.Error(), ErrDecrypt.Error()) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("vault key", func(t *testing.T) {
+		asked = nil
+		env, err := Parse(WithFs(fs), WithVault(), WithKeyProvider(keys))
+		assertNoError(t, err)
+		assertEqual(t, env["FROM"], "vault")
+		assertEqual(t, strings.Join(asked, ","), "DOTENV_KEY,DOTENV_KEY")
+	})
+
+	t.Run("provider errors", func(t *testing.T) {
+		broken := KeyProviderFunc(func(context.Context, string) (string, error) {
+			return "", errors.New("keychain locked")
+		})
+		_, err := Parse(WithFs(fs), WithDecryption(), WithKeyProvider(broken))
+		if err == nil || !strings.Contains(err.Error(), "keychain locked") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func Test_credentialBlob(t *testing.T) {
+	utf16le := func(s string) []byte {
+		var b []byte
+		for _, u := range utf16.Encode([]rune(s)) {
+			b = binary.LittleEndian.AppendUint16(b, u)
+		}
+		return b
+	}
+	tests := []struct {
+		name string
+		blob []byte
+		want string
+	}{
+		{"utf-8", []byte("abcd1234"), "abcd1234"},
+		{"odd utf-8", []byte("abc"), "abc"},
+		{"utf-16", utf16le("abcd1234"), "abcd1234"},
+		{"utf-16 non-ascii", utf16le("ключ"), "ключ"},
+		{"utf-16 terminated", utf16le("key\x00"), "key"},
+		{"empty", nil, ""},
+		{"unpaired surrogate", []byte{0x00, 0xd8, 0x41, 0x00}, "\x00\xd8A\x00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertEqual(t, credentialBlob(tt.blob), tt.want)
+		})
+	}
+}
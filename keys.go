@@ -0,0 +1,161 @@
+package dotenv
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// WithKeyNormalizer rewrites every key as it is parsed, before duplicates are
+// detected, files are merged and values are exported. Expansion references
+// use the normalized names. Keys normalized to "" are dropped. See
+// NormalizeUpperSnake.
+func WithKeyNormalizer(normalize func(key string) string) Option {
+	return func(o *Options) {
+		o.KeyNormalizer = normalize
+	}
+}
+
+// NormalizeUpperSnake converts a key to a conventional environment variable
+// name: surrounding whitespace is trimmed, camelCase words are split with
+// '_', letters are upper-cased and any other character that is not a digit
+// or '_' becomes '_'. "db.host" and "dbHost" become "DB_HOST", and
+// "HTTPServer" becomes "HTTP_SERVER".
+func NormalizeUpperSnake(key string) string {
+	key = strings.TrimSpace(key)
+	isLower := func(c byte) bool { return 'a' <= c && c <= 'z' }
+	isUpper := func(c byte) bool { return 'A' <= c && c <= 'Z' }
+	isDigit := func(c byte) bool { return '0' <= c && c <= '9' }
+
+	var b strings.Builder
+	b.Grow(len(key) + 4)
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if i > 0 && isUpper(c) {
+			prev := key[i-1]
+			acronymEnd := isUpper(prev) && i+1 < len(key) && isLower(key[i+1])
+			if isLower(prev) || isDigit(prev) || acronymEnd {
+				b.WriteByte('_')
+			}
+		}
+		switch {
+		case isLower(c):
+			b.WriteByte(c - 'a' + 'A')
+		case isUpper(c), isDigit(c), c == '_':
+			b.WriteByte(c)
+		case c < utf8.RuneSelf:
+			b.WriteByte('_')
+		default:
+			// A multi-byte rune becomes a single '_', like any other
+			// character.
+			_, size := utf8.DecodeRuneInString(key[i:])
+			i += size - 1
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// WithOnlyPrefix keeps only keys starting with prefix and drops the rest.
+func WithOnlyPrefix(prefix string) Option {
+	return func(o *Options) {
+		o.OnlyPrefix = prefix
+	}
+}
+
+// WithStripPrefix removes prefix from keys that start with it, so APP_PORT
+// is exported as PORT. A stripped key wins over an unprefixed key of the same
+// name. Combine with WithOnlyPrefix to load a single namespace.
+func WithStripPrefix(prefix string) Option {
+	return func(o *Options) {
+		o.StripPrefix = prefix
+	}
+}
+
+// WithPrefix prepends prefix to every key, after WithOnlyPrefix and
+// WithStripPrefix were applied, so values end up namespaced in the
+// environment.
+func WithPrefix(prefix string) Option {
+	return func(o *Options) {
+		o.Prefix = prefix
+	}
+}
+
+// WithAllowKeys restricts loading to the listed keys; everything else in the
+// files is dropped. It may be combined with WithAllowKeysMatching, in which
+// case a key has to match either. Keys are compared after WithStripPrefix.
+func WithAllowKeys(keys ...string) Option {
+	return func(o *Options) {
+		o.AllowKeys = append(o.AllowKeys, keys...)
+	}
+}
+
+// WithDenyKeys drops the listed keys. Denying wins over allowing.
+func WithDenyKeys(keys ...string) Option {
+	return func(o *Options) {
+		o.DenyKeys = append(o.DenyKeys, keys...)
+	}
+}
+
+// WithAllowKeysMatching is like WithAllowKeys for keys matching re.
+func WithAllowKeysMatching(re *regexp.Regexp) Option {
+	return func(o *Options) {
+		o.AllowPatterns = append(o.AllowPatterns, re)
+	}
+}
+
+// WithDenyKeysMatching is like WithDenyKeys for keys matching re.
+func WithDenyKeysMatching(re *regexp.Regexp) Option {
+	return func(o *Options) {
+		o.DenyPatterns = append(o.DenyPatterns, re)
+	}
+}
+
+// selectKeys applies the prefix and allow/deny options to the resolved
+// entries. Required keys and schemas refer to the resulting names.
+func selectKeys(opts Options, env entries) entries {
+	if opts.OnlyPrefix == "" && opts.StripPrefix == "" && opts.Prefix == "" &&
+		len(opts.AllowKeys) == 0 && len(opts.AllowPatterns) == 0 &&
+		len(opts.DenyKeys) == 0 && len(opts.DenyPatterns) == 0 {
+		return env
+	}
+
+	selected := make(entries, len(env))
+	add := func(name string, e entry) {
+		if name != "" && opts.keyAllowed(name) {
+			selected[opts.Prefix+name] = e
+		}
+	}
+	var stripped []string
+	for key, e := range env {
+		switch {
+		case !strings.HasPrefix(key, opts.OnlyPrefix):
+		case opts.StripPrefix != "" && strings.HasPrefix(key, opts.StripPrefix):
+			stripped = append(stripped, key)
+		default:
+			add(key, e)
+		}
+	}
+	// Stripped keys go last so that they override unprefixed ones.
+	for _, key := range stripped {
+		add(strings.TrimPrefix(key, opts.StripPrefix), env[key])
+	}
+	return selected
+}
+
+func (o Options) keyAllowed(key string) bool {
+	if slices.Contains(o.DenyKeys, key) || slices.ContainsFunc(o.DenyPatterns, matches(key)) {
+		return false
+	}
+	if len(o.AllowKeys) == 0 && len(o.AllowPatterns) == 0 {
+		return true
+	}
+	return slices.Contains(o.AllowKeys, key) || slices.ContainsFunc(o.AllowPatterns, matches(key))
+}
+
+func matches(key string) func(*regexp.Regexp) bool {
+	return func(re *regexp.Regexp) bool {
+		return re.MatchString(key)
+	}
+}
@@ -0,0 +1,101 @@
+package dotenv
+
+import (
+	"regexp"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_keyNormalizer(t *testing.T) {
+	for in, want := range map[string]string{
+		" db.host ":   "DB_HOST",
+		"api-key":     "API_KEY",
+		"Already_OK2": "ALREADY_OK2",
+		"dbHost":      "DB_HOST",
+		"DbHost":      "DB_HOST",
+		"db_Host":     "DB_HOST",
+		"HTTPServer":  "HTTP_SERVER",
+		"apiURL":      "API_URL",
+		"ipv4Addr":    "IPV4_ADDR",
+		"ALL_CAPS":    "ALL_CAPS",
+		"héllo":       "H_LLO",
+	} {
+		assertEqual(t, NormalizeUpperSnake(in), want)
+	}
+
+	env, err := ParseString("db.host=localhost\nDB_PORT=5432\nurl=${DB_HOST}:${DB_PORT}\n",
+		WithKeyNormalizer(NormalizeUpperSnake), WithExpand(true))
+	assertNoError(t, err)
+	assertEqual(t, env["DB_HOST"], "localhost")
+	assertEqual(t, env["URL"], "localhost:5432")
+	_, ok := env["db.host"]
+	assertEqual(t, ok, false)
+
+	_, err = ParseString("a.b=1\nA_B=2\n", WithKeyNormalizer(NormalizeUpperSnake), WithDuplicatePolicy(DuplicateError))
+	if err == nil {
+		t.Fatal("expected duplicate after normalization")
+	}
+}
+
+func Test_prefixes(t *testing.T) {
+	const content = "APP_PORT=8080\nAPP_HOST=example.com\nPORT=1\nOTHER=x\n"
+
+	t.Run("only prefix", func(t *testing.T) {
+		env, err := ParseString(content, WithOnlyPrefix("APP_"))
+		assertNoError(t, err)
+		assertEqual(t, len(env), 2)
+		assertEqual(t, env["APP_PORT"], "8080")
+	})
+
+	t.Run("strip prefix wins over unprefixed key", func(t *testing.T) {
+		env, err := ParseString(content, WithStripPrefix("APP_"))
+		assertNoError(t, err)
+		assertEqual(t, len(env), 3)
+		assertEqual(t, env["PORT"], "8080")
+		assertEqual(t, env["HOST"], "example.com")
+		assertEqual(t, env["OTHER"], "x")
+	})
+
+	t.Run("only and strip load a namespace", func(t *testing.T) {
+		env, err := ParseString(content, WithOnlyPrefix("APP_"), WithStripPrefix("APP_"))
+		assertNoError(t, err)
+		assertEqual(t, len(env), 2)
+		assertEqual(t, env["PORT"], "8080")
+	})
+
+	t.Run("prefix is added last", func(t *testing.T) {
+		env, err := ParseString(content, WithOnlyPrefix("APP_"), WithStripPrefix("APP_"), WithPrefix("TENANT1_"))
+		assertNoError(t, err)
+		assertEqual(t, len(env), 2)
+		assertEqual(t, env["TENANT1_PORT"], "8080")
+		assertEqual(t, env["TENANT1_HOST"], "example.com")
+	})
+
+	t.Run("required keys use renamed names", func(t *testing.T) {
+		fs := fstest.MapFS{".env": &fstest.MapFile{Data: []byte(content)}}
+		_, err := Parse(WithFs(fs), WithStripPrefix("APP_"), WithRequired("HOST"))
+		assertNoError(t, err)
+	})
+}
+
+func Test_allowDenyKeys(t *testing.T) {
+	const content = "APP_PORT=8080\nAPP_SECRET=s\nDEBUG=1\nPATH=/evil\n"
+
+	env, err := ParseString(content, WithAllowKeys("DEBUG"), WithAllowKeysMatching(regexp.MustCompile(`^APP_`)),
+		WithDenyKeys("APP_SECRET"))
+	assertNoError(t, err)
+	assertEqual(t, len(env), 2)
+	assertEqual(t, env["APP_PORT"], "8080")
+	assertEqual(t, env["DEBUG"], "1")
+
+	env, err = ParseString(content, WithDenyKeysMatching(regexp.MustCompile(`^(PATH|LD_.*)$`)))
+	assertNoError(t, err)
+	assertEqual(t, len(env), 3)
+	_, ok := env["PATH"]
+	assertEqual(t, ok, false)
+
+	env, err = ParseString(content, WithStripPrefix("APP_"), WithAllowKeys("PORT"))
+	assertNoError(t, err)
+	assertEqual(t, len(env), 1)
+	assertEqual(t, env["PORT"], "8080")
+}
@@ -0,0 +1,90 @@
+package dotenv
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// KubeManifest describes the object written by ToSecret and ToConfigMap.
+type KubeManifest struct {
+	Name string
+	// Namespace is left out of the manifest when empty.
+	Namespace string
+	// StringData makes ToSecret write values in plain text under stringData
+	// instead of base64-encoded under data.
+	StringData bool
+}
+
+// ToSecret renders env as a Kubernetes Secret manifest of type Opaque.
+// Keys must consist of letters, digits, '-', '_' and '.'.
+func ToSecret(env map[string]string, m KubeManifest) ([]byte, error) {
+	field := "data"
+	encode := func(v string) string { return base64.StdEncoding.EncodeToString([]byte(v)) }
+	if m.StringData {
+		field = "stringData"
+		encode = func(v string) string { return v }
+	}
+	return kubeManifest("Secret", "type: Opaque\n", field, env, m, encode)
+}
+
+// ToConfigMap renders env as a Kubernetes ConfigMap manifest. Keys must
+// consist of letters, digits, '-', '_' and '.'.
+func ToConfigMap(env map[string]string, m KubeManifest) ([]byte, error) {
+	return kubeManifest("ConfigMap", "", "data", env, m, func(v string) string { return v })
+}
+
+func kubeManifest(kind, extra, field string, env map[string]string, m KubeManifest, encode func(string) string) ([]byte, error) {
+	if m.Name == "" {
+		return nil, errors.New("kubernetes manifest: name is required")
+	}
+
+	var b strings.Builder
+	b.WriteString("apiVersion: v1\nkind: " + kind + "\nmetadata:\n")
+	for _, meta := range [][2]string{{"name", m.Name}, {"namespace", m.Namespace}} {
+		if meta[1] == "" {
+			continue
+		}
+		v, err := yamlString(meta[1])
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString("  " + meta[0] + ": " + v + "\n")
+	}
+	b.WriteString(extra)
+	if len(env) == 0 {
+		b.WriteString(field + ": {}\n")
+		return []byte(b.String()), nil
+	}
+
+	b.WriteString(field + ":\n")
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		if !validKubeKey(key) {
+			return nil, fmt.Errorf("kubernetes manifest: invalid key %q", key)
+		}
+		k, err := yamlKey(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := yamlString(encode(env[key]))
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString("  " + k + ": " + v + "\n")
+	}
+	return []byte(b.String()), nil
+}
+
+// validKubeKey reports whether key is allowed in the data of a ConfigMap or
+// Secret.
+func validKubeKey(key string) bool {
+	if key == "" || len(key) > 253 {
+		return false
+	}
+	return strings.IndexFunc(key, func(r rune) bool {
+		return !(r == '-' || r == '_' || r == '.' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}) < 0
+}
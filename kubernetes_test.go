@@ -0,0 +1,56 @@
+package dotenv
+
+import "testing"
+
+func Test_kubernetesManifests(t *testing.T) {
+	env := map[string]string{"DB_PASSWORD": "s3cret", "PORT": "8080"}
+
+	out, err := ToSecret(env, KubeManifest{Name: "app", Namespace: "prod"})
+	assertNoError(t, err)
+	assertEqual(t, string(out), `apiVersion: v1
+kind: Secret
+metadata:
+  name: "app"
+  namespace: "prod"
+type: Opaque
+data:
+  DB_PASSWORD: "czNjcmV0"
+  PORT: "ODA4MA=="
+`)
+
+	out, err = ToSecret(env, KubeManifest{Name: "app", StringData: true})
+	assertNoError(t, err)
+	assertEqual(t, string(out), `apiVersion: v1
+kind: Secret
+metadata:
+  name: "app"
+type: Opaque
+stringData:
+  DB_PASSWORD: "s3cret"
+  PORT: "8080"
+`)
+
+	out, err = ToConfigMap(env, KubeManifest{Name: "app"})
+	assertNoError(t, err)
+	assertEqual(t, string(out), `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: "app"
+data:
+  DB_PASSWORD: "s3cret"
+  PORT: "8080"
+`)
+
+	back, err := ParseString(string(out), WithFormat(FormatYAML))
+	assertNoError(t, err)
+	assertEqual(t, back["data.PORT"], "8080")
+
+	_, err = ToConfigMap(map[string]string{"bad key": "x"}, KubeManifest{Name: "app"})
+	if err == nil {
+		t.Fatal("expected error for invalid key")
+	}
+	_, err = ToConfigMap(env, KubeManifest{})
+	if err == nil {
+		t.Fatal("expected error for missing name")
+	}
+}
@@ -0,0 +1,190 @@
+package dotenv
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ConsulProvider lists the keys under Prefix in Consul's KV store using its
+// HTTP API. Key names are mapped to variables by dropping Prefix and
+// converting the rest with NormalizeUpperSnake, so app/db/host under app/
+// becomes DB_HOST. A missing prefix yields no values.
+type ConsulProvider struct {
+	// Address of the agent, http://127.0.0.1:8500 if empty.
+	Address    string
+	Prefix     string
+	Token      string
+	Datacenter string
+	// Client sends the requests, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// Fetch reads all keys under Prefix.
+func (p *ConsulProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	addr := p.Address
+	if addr == "" {
+		addr = "http://127.0.0.1:8500"
+	}
+	q := url.Values{"recurse": {"true"}}
+	if p.Datacenter != "" {
+		q.Set("dc", p.Datacenter)
+	}
+	u := strings.TrimSuffix(addr, "/") + "/v1/kv/" + strings.TrimPrefix(p.Prefix, "/") + "?" + q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.Token != "" {
+		req.Header.Set("X-Consul-Token", p.Token)
+	}
+
+	var pairs []struct {
+		Key   string
+		Value *string
+	}
+	status, err := doJSON(p.Client, req, &pairs)
+	if status == http.StatusNotFound {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("consul %s: %w", p.Prefix, err)
+	}
+
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		if pair.Value == nil {
+			continue
+		}
+		v, err := base64.StdEncoding.DecodeString(*pair.Value)
+		if err != nil {
+			return nil, fmt.Errorf("consul %s: decode %s: %w", p.Prefix, pair.Key, err)
+		}
+		if key := kvKey(pair.Key, p.Prefix); key != "" {
+			values[key] = string(v)
+		}
+	}
+	return values, nil
+}
+
+// String returns a consul:// URL naming the prefix.
+func (p *ConsulProvider) String() string {
+	return "consul://" + p.Prefix
+}
+
+// EtcdProvider lists the keys under Prefix in etcd using the JSON gateway
+// of its v3 API. Keys are mapped to variables like ConsulProvider does.
+type EtcdProvider struct {
+	// Endpoint of a cluster member, http://127.0.0.1:2379 if empty.
+	Endpoint string
+	Prefix   string
+	// Token is an auth token from /v3/auth/authenticate, if auth is enabled.
+	Token string
+	// Client sends the requests, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// Fetch reads all keys under Prefix.
+func (p *EtcdProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = "http://127.0.0.1:2379"
+	}
+	body, err := json.Marshal(map[string]string{
+		"key":       base64.StdEncoding.EncodeToString([]byte(p.Prefix)),
+		"range_end": base64.StdEncoding.EncodeToString(prefixEnd([]byte(p.Prefix))),
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/v3/kv/range", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.Token != "" {
+		req.Header.Set("Authorization", p.Token)
+	}
+
+	var resp struct {
+		KVs []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"kvs"`
+	}
+	if _, err := doJSON(p.Client, req, &resp); err != nil {
+		return nil, fmt.Errorf("etcd %s: %w", p.Prefix, err)
+	}
+
+	values := make(map[string]string, len(resp.KVs))
+	for _, kv := range resp.KVs {
+		k, err := base64.StdEncoding.DecodeString(kv.Key)
+		if err != nil {
+			return nil, fmt.Errorf("etcd %s: decode key: %w", p.Prefix, err)
+		}
+		v, err := base64.StdEncoding.DecodeString(kv.Value)
+		if err != nil {
+			return nil, fmt.Errorf("etcd %s: decode %s: %w", p.Prefix, k, err)
+		}
+		if key := kvKey(string(k), p.Prefix); key != "" {
+			values[key] = string(v)
+		}
+	}
+	return values, nil
+}
+
+// String returns an etcd:// URL naming the prefix.
+func (p *EtcdProvider) String() string {
+	return "etcd://" + p.Prefix
+}
+
+// kvKey maps a KV store key below prefix to a variable name.
+func kvKey(key, prefix string) string {
+	rest := strings.Trim(strings.TrimPrefix(key, prefix), "/")
+	return NormalizeUpperSnake(rest)
+}
+
+// prefixEnd returns the smallest key greater than every key starting with
+// prefix, as etcd range requests expect.
+func prefixEnd(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	// All bytes are 0xff: range to the end of the keyspace.
+	return []byte{0}
+}
+
+// doJSON sends req and decodes a 200 response into v. It returns the status
+// code so that callers can treat some failures specially.
+func doJSON(client *http.Client, req *http.Request, v any) (int, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if s := strings.TrimSpace(string(msg)); s != "" {
+			return resp.StatusCode, fmt.Errorf("unexpected status %s: %s", resp.Status, s)
+		}
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return resp.StatusCode, fmt.Errorf("decode response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
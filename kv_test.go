@@ -0,0 +1,67 @@
+package dotenv
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_consulProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Consul-Token") != "tok" || r.URL.Query().Get("recurse") != "true" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/kv/app/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`[
+			{"Key": "app/", "Value": null},
+			{"Key": "app/db/host", "Value": "` + base64.StdEncoding.EncodeToString([]byte("db.local")) + `"},
+			{"Key": "app/log-level", "Value": "` + base64.StdEncoding.EncodeToString([]byte("debug")) + `"}
+		]`))
+	}))
+	defer srv.Close()
+
+	env, err := (&ConsulProvider{Address: srv.URL, Prefix: "app/", Token: "tok"}).Fetch(context.Background())
+	assertNoError(t, err)
+	assertEqual(t, len(env), 2)
+	assertEqual(t, env["DB_HOST"], "db.local")
+	assertEqual(t, env["LOG_LEVEL"], "debug")
+
+	env, err = (&ConsulProvider{Address: srv.URL, Prefix: "missing/", Token: "tok"}).Fetch(context.Background())
+	assertNoError(t, err)
+	assertEqual(t, len(env), 0)
+
+	_, err = (&ConsulProvider{Address: srv.URL, Prefix: "app/"}).Fetch(context.Background())
+	if err == nil {
+		t.Fatal("expected error without token")
+	}
+}
+
+func Test_etcdProvider(t *testing.T) {
+	b64 := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		if r.URL.Path != "/v3/kv/range" || req["key"] != b64("/app/") || req["range_end"] != b64("/app0") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"kvs": []map[string]string{
+			{"key": b64("/app/db/host"), "value": b64("db.local")},
+		}})
+	}))
+	defer srv.Close()
+
+	env, err := (&EtcdProvider{Endpoint: srv.URL, Prefix: "/app/"}).Fetch(context.Background())
+	assertNoError(t, err)
+	assertEqual(t, len(env), 1)
+	assertEqual(t, env["DB_HOST"], "db.local")
+
+	assertEqual(t, string(prefixEnd([]byte{'a', 0xff})), "b")
+}
@@ -0,0 +1,214 @@
+package dotenv
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// LockFile is the name of the lockfile written by Lock and read by
+// WithVerifyLock.
+const LockFile = ".env.lock"
+
+// ErrLockMismatch is wrapped by the errors of WithVerifyLock.
+var ErrLockMismatch = errors.New("lockfile mismatch")
+
+// lockEntry is one locked file.
+type lockEntry struct {
+	sum  string
+	keys string
+	// included is set for files only reached through include directives.
+	// They are verified when read but not required to be.
+	included bool
+}
+
+// includePrefix marks the names of included files in LockFile.
+const includePrefix = "include:"
+
+// Lock atomically writes LockFile to the current directory, recording the
+// SHA-256 of every file in paths and a fingerprint of the keys it defines.
+// paths default to .env. Files named by include directives (see
+// WithIncludes) are recorded too, recursively. Each line has the form
+//
+//	sha256:<hex> keys:<hex> <path>
+//
+// with "include:" in front of the path of included files. Commit the
+// lockfile and load with WithVerifyLock to detect files that were changed
+// without updating it.
+func Lock(paths ...string) error {
+	if len(paths) == 0 {
+		paths = []string{".env"}
+	}
+	var (
+		names   []string
+		entries = map[string]lockEntry{}
+	)
+	var add func(name string, included bool) error
+	add = func(name string, included bool) error {
+		if e, ok := entries[name]; ok {
+			e.included = e.included && included
+			entries[name] = e
+			return nil
+		}
+		data, err := os.ReadFile(filepath.FromSlash(name))
+		if err != nil {
+			return err
+		}
+		entry, err := lockEntryOf(data, name)
+		if err != nil {
+			return err
+		}
+		entry.included = included
+		names = append(names, name)
+		entries[name] = entry
+		for _, target := range lockIncludes(data, name) {
+			if err := add(target, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, p := range paths {
+		if err := add(path.Clean(filepath.ToSlash(p)), false); err != nil {
+			return fmt.Errorf("lock: %w", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by dotenv.Lock. Do not edit.\n")
+	for _, name := range names {
+		entry := entries[name]
+		if entry.included {
+			name = includePrefix + name
+		}
+		fmt.Fprintf(&buf, "sha256:%s keys:%s %s\n", entry.sum, entry.keys, name)
+	}
+	return writeFileAtomic(LockFile, buf.Bytes(), 0o644)
+}
+
+// lockIncludes returns the files included by data, resolved like
+// includeFile does.
+func lockIncludes(data []byte, name string) []string {
+	opts := applyOptions([]Option{WithIncludes()})
+	var targets []string
+	scanStatements(opts, bytes.NewReader(data), name, func(st statement) error {
+		if st.include != "" {
+			targets = append(targets, path.Join(path.Dir(name), st.include))
+		}
+		return nil
+	})
+	return targets
+}
+
+// WithVerifyLock makes loading fail with ErrLockMismatch when a file
+// listed in LockFile was changed, is missing, or a file not listed in it is
+// read, including files read through include directives. The error tells
+// apart changed values from added or removed keys. Paths in the lockfile are
+// relative to the file system root, see WithFs.
+func WithVerifyLock() Option {
+	return func(o *Options) {
+		o.VerifyLock = true
+	}
+}
+
+// lockEntryOf hashes data and the keys it defines.
+func lockEntryOf(data []byte, name string) (lockEntry, error) {
+	sum := sha256.Sum256(data)
+	env, err := parseReader(context.Background(), bytes.NewReader(data), name, nil)
+	if err != nil {
+		return lockEntry{}, err
+	}
+	keys := sha256.Sum256([]byte(strings.Join(slices.Sorted(maps.Keys(env)), "\n")))
+	return lockEntry{sum: hex.EncodeToString(sum[:]), keys: hex.EncodeToString(keys[:])}, nil
+}
+
+// lockVerifier checks the files read by parse against LockFile.
+type lockVerifier struct {
+	locked map[string]lockEntry
+
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+func readLock(fsys fs.FS) (*lockVerifier, error) {
+	data, err := fs.ReadFile(fsys, LockFile)
+	if err != nil {
+		return nil, fmt.Errorf("read lockfile: %w", err)
+	}
+	v := &lockVerifier{locked: map[string]lockEntry{}, seen: map[string]bool{}}
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sum, rest, ok1 := strings.Cut(line, " ")
+		keys, name, ok2 := strings.Cut(rest, " ")
+		sum, ok3 := strings.CutPrefix(sum, "sha256:")
+		keys, ok4 := strings.CutPrefix(keys, "keys:")
+		if !ok1 || !ok2 || !ok3 || !ok4 || name == "" {
+			return nil, &ParseError{File: LockFile, Line: i + 1, Col: 1, Reason: "malformed lockfile entry"}
+		}
+		name, included := strings.CutPrefix(name, includePrefix)
+		v.locked[path.Clean(name)] = lockEntry{sum: sum, keys: keys, included: included}
+	}
+	return v, nil
+}
+
+// reader returns r and a function checking what was read from it against
+// the lockfile entry of envPath.
+func (v *lockVerifier) reader(r io.Reader, envPath string) (io.Reader, func() error) {
+	if v == nil {
+		return r, func() error { return nil }
+	}
+	var buf bytes.Buffer
+	return io.TeeReader(r, &buf), func() error {
+		return v.verify(buf.Bytes(), envPath)
+	}
+}
+
+func (v *lockVerifier) verify(data []byte, envPath string) error {
+	v.mu.Lock()
+	v.seen[envPath] = true
+	v.mu.Unlock()
+	want, ok := v.locked[envPath]
+	if !ok {
+		return fmt.Errorf("%w: %s is not in %s", ErrLockMismatch, envPath, LockFile)
+	}
+	got, err := lockEntryOf(data, envPath)
+	if err != nil {
+		return err
+	}
+	switch {
+	case got.keys != want.keys:
+		return fmt.Errorf("%w: keys of %s changed", ErrLockMismatch, envPath)
+	case got.sum != want.sum:
+		return fmt.Errorf("%w: values of %s changed", ErrLockMismatch, envPath)
+	}
+	return nil
+}
+
+// missing reports locked files that were not read.
+func (v *lockVerifier) missing() error {
+	if v == nil {
+		return nil
+	}
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(v.locked)) {
+		if !v.seen[name] && !v.locked[name].included {
+			errs = append(errs, fmt.Errorf("%w: %s is missing", ErrLockMismatch, name))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,127 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func Test_lock(t *testing.T) {
+	t.Chdir(t.TempDir())
+	write := func(name, data string) {
+		t.Helper()
+		assertNoError(t, os.WriteFile(name, []byte(data), 0o600))
+	}
+	write(".env", "A=1\nB=2\n")
+	write(".env.local", "C=3\n")
+	assertNoError(t, Lock(".env", "./.env.local"))
+
+	data, err := os.ReadFile(LockFile)
+	assertNoError(t, err)
+	if !strings.Contains(string(data), " .env\n") || !strings.Contains(string(data), " .env.local\n") {
+		t.Fatalf("unexpected lockfile:\n%s", data)
+	}
+
+	parse := func() error {
+		_, err := Parse(WithPaths(".env", ".env.local"), WithVerifyLock())
+		return err
+	}
+	assertNoError(t, parse())
+
+	t.Run("values changed", func(t *testing.T) {
+		write(".env", "A=1\nB=3\n")
+		t.Cleanup(func() { write(".env", "A=1\nB=2\n") })
+		err := parse()
+		if !errors.Is(err, ErrLockMismatch) || !strings.Contains(err.Error(), "values of .env changed") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("keys changed", func(t *testing.T) {
+		write(".env", "A=1\nB=2\nEVIL=1\n")
+		t.Cleanup(func() { write(".env", "A=1\nB=2\n") })
+		err := parse()
+		if !errors.Is(err, ErrLockMismatch) || !strings.Contains(err.Error(), "keys of .env changed") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("unlisted and missing files", func(t *testing.T) {
+		write(".env.extra", "D=4\n")
+		_, err := Parse(WithPaths(".env", ".env.extra"), WithVerifyLock())
+		if !errors.Is(err, ErrLockMismatch) || !strings.Contains(err.Error(), ".env.extra is not in .env.lock") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = Parse(WithPaths(".env"), WithVerifyLock())
+		if !errors.Is(err, ErrLockMismatch) || !strings.Contains(err.Error(), ".env.local is missing") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("no lockfile", func(t *testing.T) {
+		assertNoError(t, os.Remove(LockFile))
+		if err := parse(); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("want ErrNotExist, got %v", err)
+		}
+	})
+}
+
+func Test_lockIncludes(t *testing.T) {
+	t.Chdir(t.TempDir())
+	write := func(name, data string) {
+		t.Helper()
+		assertNoError(t, os.MkdirAll(filepath.Dir(name), 0o700))
+		assertNoError(t, os.WriteFile(name, []byte(data), 0o600))
+	}
+	write(".env", "A=1\n#include shared/base.env\n")
+	write("shared/base.env", "B=2\nsource ./more.env\n")
+	write("shared/more.env", "C=3\n")
+	assertNoError(t, Lock())
+
+	data, err := os.ReadFile(LockFile)
+	assertNoError(t, err)
+	for _, want := range []string{" .env\n", " include:shared/base.env\n", " include:shared/more.env\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("missing %q in lockfile:\n%s", want, data)
+		}
+	}
+
+	parse := func(opts ...Option) error {
+		_, err := Parse(append([]Option{WithVerifyLock()}, opts...)...)
+		return err
+	}
+	assertNoError(t, parse(WithIncludes()))
+	assertNoError(t, parse())
+
+	write("shared/more.env", "C=4\n")
+	err = parse(WithIncludes())
+	if !errors.Is(err, ErrLockMismatch) || !strings.Contains(err.Error(), "values of shared/more.env changed") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	write("shared/more.env", "C=3\n")
+	write("shared/base.env", "B=2\nsource ./more.env\nsource ./extra.env\n")
+	write("shared/extra.env", "D=5\n")
+	err = parse(WithIncludes())
+	if !errors.Is(err, ErrLockMismatch) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func Test_lockIsAtomic(t *testing.T) {
+	t.Chdir(t.TempDir())
+	assertNoError(t, os.WriteFile(".env", []byte("A=1\n"), 0o600))
+	assertNoError(t, Lock())
+
+	entries, err := os.ReadDir(".")
+	assertNoError(t, err)
+	assertEqual(t, len(entries), 2)
+	info, err := os.Stat(LockFile)
+	assertNoError(t, err)
+	if runtime.GOOS != "windows" {
+		assertEqual(t, info.Mode().Perm(), os.FileMode(0o644))
+	}
+}
@@ -0,0 +1,45 @@
+package dotenv
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Marshal serializes env into dotenv format with keys sorted. Values are
+// written bare when that is unambiguous, single-quoted otherwise and
+// double-quoted when they contain a single quote, so the output reads back
+// unchanged with the default parser options. Single quotes also keep
+// WithExpand from touching values; a double-quoted value containing '$' is
+// expanded by it. Values holding a single quote together with a double quote
+// or a line break are double-quoted with backslash escapes and need
+// WithEscapes(true) to read back.
+func Marshal(env map[string]string) ([]byte, error) {
+	var b strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		if err := validateKey(key); err != nil {
+			return nil, fmt.Errorf("marshal: %w", err)
+		}
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(quoteValue(env[key]))
+		b.WriteByte('\n')
+	}
+	return []byte(b.String()), nil
+}
+
+// quoteValue returns val in the form Marshal writes it.
+func quoteValue(val string) string {
+	switch {
+	case !strings.ContainsAny(val, " \t\r\n#'\"\\$`"):
+		return val
+	case !strings.Contains(val, "'"):
+		return "'" + val + "'"
+	case !strings.ContainsAny(val, "\"\r\n"):
+		return `"` + val + `"`
+	default:
+		r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+		return `"` + r.Replace(val) + `"`
+	}
+}
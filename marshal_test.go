@@ -0,0 +1,88 @@
+package dotenv
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_marshal(t *testing.T) {
+	t.Run("quotes values only when needed", func(t *testing.T) {
+		out, err := Marshal(map[string]string{
+			"PLAIN":   "value",
+			"EMPTY":   "",
+			"SPACES":  "John Doe",
+			"HASH":    "a #b",
+			"DOUBLE":  `say "hi"`,
+			"SINGLE":  "it's",
+			"NEWLINE": "line1\nline2",
+		})
+		assertNoError(t, err)
+		assertEqual(t, string(out), `DOUBLE='say "hi"'
+EMPTY=
+HASH='a #b'
+NEWLINE='line1
+line2'
+PLAIN=value
+SINGLE="it's"
+SPACES='John Doe'
+`)
+	})
+
+	t.Run("round trips through Parse", func(t *testing.T) {
+		in := map[string]string{
+			"A": "plain",
+			"B": "with spaces and # hash",
+			"C": "multi\nline\n",
+			"D": `quote " and $dollar`,
+			"E": `it's`,
+			"F": "  padded  ",
+		}
+		out, err := Marshal(in)
+		assertNoError(t, err)
+		got, err := ParseString(string(out), WithExpand(true))
+		assertNoError(t, err)
+		for k, v := range in {
+			assertEqual(t, got[k], v)
+		}
+	})
+
+	t.Run("round trips with default options", func(t *testing.T) {
+		tests := []struct {
+			name, value string
+		}{
+			{"backslash", `a\b`},
+			{"dollar", "$HOME"},
+			{"backtick", "`cmd`"},
+			{"single quote and backslash", `it's a\b`},
+			{"single quote and dollar", "it's $5"},
+			{"single quote and backtick", "it's `cmd`"},
+			{"double quote and backslash", `say "hi" \n`},
+			{"tab", "a\tb"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				out, err := Marshal(map[string]string{"K": tt.value})
+				assertNoError(t, err)
+				got, err := ParseString(string(out))
+				assertNoError(t, err)
+				assertEqual(t, got["K"], tt.value)
+			})
+		}
+	})
+
+	t.Run("escapes values with both quote kinds", func(t *testing.T) {
+		in := map[string]string{"MIX": "it's \"quoted\"\n\\$"}
+		out, err := Marshal(in)
+		assertNoError(t, err)
+		got, err := ParseString(string(out), WithEscapes(true))
+		assertNoError(t, err)
+		assertEqual(t, got["MIX"], in["MIX"])
+	})
+
+	t.Run("rejects invalid keys", func(t *testing.T) {
+		_, err := Marshal(map[string]string{"BAD KEY": "1"})
+		if err == nil || !strings.Contains(err.Error(), "BAD KEY") {
+			t.Fatalf("expected invalid key error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package dotenv
+
+import (
+	"errors"
+	"time"
+)
+
+// Metrics receives counts and timings of loading, for example to update
+// Prometheus counters in a service that reloads its configuration. Methods
+// are called synchronously from the loading goroutine.
+type Metrics interface {
+	// FileLoaded is called for every file, key directory and provider read
+	// successfully, with the number of assignments read from it.
+	FileLoaded(path string, keys int)
+	// KeysSet and KeysSkipped are called once per Load with the number of
+	// variables exported and left alone because of WithNoOverride.
+	KeysSet(n int)
+	KeysSkipped(n int)
+	// ParseErrors is called with the number of *ParseError a failed parse
+	// reported; more than one in strict mode.
+	ParseErrors(n int)
+	// LoadDuration is called once per parse with the time spent reading and
+	// parsing, and the error it failed with, if any.
+	LoadDuration(d time.Duration, err error)
+}
+
+// WithMetrics reports counts and timings to m.
+func WithMetrics(m Metrics) Option {
+	return func(o *Options) {
+		o.Metrics = m
+	}
+}
+
+// countParseErrors counts the *ParseError values in the tree of err.
+func countParseErrors(err error) int {
+	switch e := err.(type) {
+	case nil:
+		return 0
+	case *ParseError:
+		return 1
+	case interface{ Unwrap() []error }:
+		n := 0
+		for _, err := range e.Unwrap() {
+			n += countParseErrors(err)
+		}
+		return n
+	}
+	return countParseErrors(errors.Unwrap(err))
+}
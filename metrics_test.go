@@ -0,0 +1,47 @@
+package dotenv
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+type recordingMetrics struct {
+	events []string
+}
+
+func (m *recordingMetrics) FileLoaded(path string, keys int) {
+	m.events = append(m.events, fmt.Sprintf("file %s %d", path, keys))
+}
+func (m *recordingMetrics) KeysSet(n int) { m.events = append(m.events, fmt.Sprintf("set %d", n)) }
+func (m *recordingMetrics) KeysSkipped(n int) {
+	m.events = append(m.events, fmt.Sprintf("skipped %d", n))
+}
+func (m *recordingMetrics) ParseErrors(n int) {
+	m.events = append(m.events, fmt.Sprintf("parse errors %d", n))
+}
+func (m *recordingMetrics) LoadDuration(d time.Duration, err error) {
+	m.events = append(m.events, fmt.Sprintf("duration %t %v", d > 0, err != nil))
+}
+
+func Test_metrics(t *testing.T) {
+	fs := fstest.MapFS{
+		".env": &fstest.MapFile{Data: []byte("MET_A=1\nMET_B=2\n")},
+		"bad":  &fstest.MapFile{Data: []byte("1=a\nMET_C=\"open\n")},
+	}
+
+	m := &recordingMetrics{}
+	t.Setenv("MET_B", "preset")
+	_, err := LoadReport(WithFs(fs), WithMetrics(m), WithNoOverride(), WithSetter(func(string, string) error { return nil }))
+	assertNoError(t, err)
+	assertEqual(t, strings.Join(m.events, "; "), "file .env 2; duration true false; set 1; skipped 1")
+
+	m = &recordingMetrics{}
+	_, err = Parse(WithFs(fs), WithPaths("bad"), WithStrict(), WithMetrics(m))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	assertEqual(t, strings.Join(m.events, "; "), "parse errors 2; duration true true")
+}
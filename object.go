@@ -0,0 +1,97 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ObjectClient is the part of an object storage API used by ObjectProvider:
+// reading one object. The package does not depend on any cloud SDK; build
+// the client with the SDK's default credential chain and adapt it. With
+// aws-sdk-go-v2 an S3 adapter looks like this:
+//
+//	type s3Adapter struct{ c *s3.Client }
+//
+//	func (a s3Adapter) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+//		out, err := a.c.GetObject(ctx, &s3.GetObjectInput{Bucket: &bucket, Key: &key})
+//		if err != nil {
+//			return nil, err
+//		}
+//		return out.Body, nil
+//	}
+//
+// and for cloud.google.com/go/storage:
+//
+//	func (a gcsAdapter) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+//		return a.c.Bucket(bucket).Object(key).NewReader(ctx)
+//	}
+type ObjectClient interface {
+	GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error)
+}
+
+// ObjectProvider reads a dotenv file stored in object storage, addressed by
+// a URL such as s3://bucket/path/.env or gs://bucket/path/.env. The object
+// is parsed like a local file, so a .json, .yaml, .toml or .properties key
+// is read in that format under FormatAuto.
+type ObjectProvider struct {
+	Client ObjectClient
+	URL    string
+	// Options configure how the object is parsed.
+	Options []Option
+}
+
+// RegisterObjectClient makes paths of the form scheme://bucket/key read
+// through client, so that they can be listed among WithPaths:
+//
+//	dotenv.RegisterObjectClient("s3", s3Adapter{s3.NewFromConfig(cfg)})
+//	dotenv.Load(dotenv.WithPaths("s3://my-bucket/batch/.env", ".env"))
+//
+// It panics like RegisterProvider when scheme is already registered.
+func RegisterObjectClient(scheme string, client ObjectClient) {
+	if client == nil {
+		panic("dotenv: RegisterObjectClient client is nil")
+	}
+	RegisterProvider(scheme, func(url string) (Provider, error) {
+		if _, _, err := splitObjectURL(url); err != nil {
+			return nil, err
+		}
+		return &ObjectProvider{Client: client, URL: url}, nil
+	})
+}
+
+// Fetch reads and parses the object.
+func (p *ObjectProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	if p.Client == nil {
+		return nil, errors.New("object: client is required")
+	}
+	bucket, key, err := splitObjectURL(p.URL)
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.Client.GetObject(ctx, bucket, key)
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", p.URL, err)
+	}
+	defer body.Close()
+	return parseReader(ctx, body, p.URL, p.Options)
+}
+
+// String returns the object URL.
+func (p *ObjectProvider) String() string {
+	return p.URL
+}
+
+// splitObjectURL splits scheme://bucket/key into bucket and key.
+func splitObjectURL(url string) (bucket, key string, err error) {
+	_, rest, ok := strings.Cut(url, "://")
+	if ok {
+		bucket, key, ok = strings.Cut(rest, "/")
+	}
+	if !ok || bucket == "" || key == "" {
+		return "", "", fmt.Errorf("invalid object URL %q: want scheme://bucket/key", url)
+	}
+	return bucket, key, nil
+}
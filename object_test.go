@@ -0,0 +1,63 @@
+package dotenv
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+// registerMemobj keeps the registration from panicking with -count > 1.
+var registerMemobj sync.Once
+
+type memObjects map[string]string
+
+func (m memObjects) GetObject(_ context.Context, bucket, key string) (io.ReadCloser, error) {
+	data, ok := m[bucket+"/"+key]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(strings.NewReader(data)), nil
+}
+
+func Test_objectProvider(t *testing.T) {
+	objects := memObjects{
+		"cfg/batch/.env":     "A=object\nB=object\n",
+		"cfg/batch/app.json": `{"DB": {"host": "db.local"}}`,
+		"cfg/batch/bad/.env": "A",
+	}
+	registerMemobj.Do(func() { RegisterObjectClient("memobj", objects) })
+
+	t.Run("paths merge in order", func(t *testing.T) {
+		fs := fstest.MapFS{".env": &fstest.MapFile{Data: []byte("B=file\n")}}
+		env, err := Parse(WithFs(fs), WithPaths("memobj://cfg/batch/.env", ".env"))
+		assertNoError(t, err)
+		assertEqual(t, env["A"], "object")
+		assertEqual(t, env["B"], "file")
+	})
+
+	t.Run("format by extension", func(t *testing.T) {
+		p := &ObjectProvider{Client: objects, URL: "s3://cfg/batch/app.json", Options: []Option{WithFormat(FormatAuto)}}
+		env, err := p.Fetch(context.Background())
+		assertNoError(t, err)
+		assertEqual(t, env["DB_HOST"], "db.local")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := (&ObjectProvider{Client: objects, URL: "gs://cfg/missing"}).Fetch(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "gs://cfg/missing") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = (&ObjectProvider{Client: objects, URL: "s3://cfg/batch/bad/.env", Options: []Option{WithStrict()}}).Fetch(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "s3://cfg/batch/bad/.env:1") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = Parse(WithPaths("memobj://bucket-only"))
+		if err == nil {
+			t.Fatal("expected invalid URL error")
+		}
+	})
+}
@@ -0,0 +1,620 @@
+package dotenv
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ParseError describes a malformed line in a dotenv file. Line and Col are
+// 1-based. It matches ErrParse.
+type ParseError struct {
+	File string
+	Line int
+	Col  int
+	// Key is the key assigned on the line, when known.
+	Key    string
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Col, e.Reason)
+}
+
+// Is reports whether target is ErrParse.
+func (e *ParseError) Is(target error) bool {
+	return target == ErrParse
+}
+
+// ParseReader parses dotenv content from r and returns the values without
+// touching the process environment. Path related options are ignored;
+// parser options such as WithExpand, WithEscapes and WithStrict apply.
+func ParseReader(r io.Reader, userOptions ...Option) (map[string]string, error) {
+	return parseReader(context.Background(), r, "<reader>", userOptions)
+}
+
+// ParseString is like ParseReader for in-memory content.
+func ParseString(s string, userOptions ...Option) (map[string]string, error) {
+	return parseReader(context.Background(), strings.NewReader(s), "<string>", userOptions)
+}
+
+func parseReader(ctx context.Context, r io.Reader, name string, userOptions []Option) (map[string]string, error) {
+	opts := applyOptions(userOptions)
+	if opts.Logger == nil {
+		return nil, fmt.Errorf("can't parse .env content with these options: logger should be provided")
+	}
+
+	var raws []rawEntry
+	if err := parseFile(ctx, opts, contextReader{r: r, ctx: ctx}, name, &raws); err != nil {
+		return nil, err
+	}
+	env, err := resolveEntries(ctx, opts, raws)
+	if err != nil {
+		return nil, err
+	}
+	if err := transformValues(opts, env); err != nil {
+		return nil, err
+	}
+	env = selectKeys(opts, env)
+	if err := applyValueHooks(opts, env); err != nil {
+		return nil, err
+	}
+	return env.values(), nil
+}
+
+// statement is one logical entry of a dotenv file: a single physical line or,
+// for multiline quoted values, several of them.
+type statement struct {
+	// line is the 1-based number of the first physical line.
+	line int
+	// raw holds the physical lines joined with '\n'.
+	raw string
+	// prefix is the part of raw in front of the key: indentation and an
+	// optional export keyword.
+	prefix string
+	// key is empty for blank lines, comments and skipped malformed lines.
+	key   string
+	value string
+	quote byte
+	// valueCol is the 1-based column the value starts at.
+	valueCol int
+	// comment is a trailing inline comment including the whitespace in
+	// front of it.
+	comment string
+	// include is the target of an include directive, see WithIncludes.
+	include string
+	// rawKey is the key as written after prefix when it differs from key,
+	// like "host" for DB_HOST in an INI "[db]" section.
+	rawKey string
+	// section is the INI section the statement is in, see DialectINI.
+	section string
+}
+
+// writtenKey returns the key as it appears in raw.
+func (st statement) writtenKey() string {
+	if st.rawKey != "" {
+		return st.rawKey
+	}
+	return st.key
+}
+
+// scanStatements splits r into statements and calls fn for each of them,
+// including blank lines and comments. Values are unquoted and, with
+// WithEscapes, unescaped, but not expanded. In strict mode malformed lines
+// are skipped and reported together once the whole input was read.
+func scanStatements(opts Options, r io.Reader, envPath string, fn func(statement) error) error {
+	cfg := opts.parserConfig()
+	if cfg.scan != nil {
+		return cfg.scan(opts, r, envPath, fn)
+	}
+	scanner := newLineReader(r, envPath, opts.MaxLineLength)
+	lineNo := 0
+	var problems []error
+	var section string
+	var conds *conditions
+	if opts.Conditionals {
+		conds = &conditions{env: opts.ConditionEnv}
+	}
+	readErr := func(err error) error {
+		if _, ok := err.(*ParseError); ok {
+			return joinErrors(append(problems, err))
+		}
+		return fmt.Errorf("read %s: %w", envPath, err)
+	}
+	for scanner.Scan() {
+		lineNo++
+		raw := scanner.Text()
+		st := statement{line: lineNo, raw: raw}
+		line := strings.TrimLeft(raw, " \t")
+		if cfg.TrimSpace {
+			line = strings.TrimSpace(raw)
+		}
+		if conds != nil {
+			ok, reason := conds.directive(line)
+			if reason != "" {
+				return joinErrors(append(problems, &ParseError{File: envPath, Line: lineNo, Col: len(raw) - len(strings.TrimLeft(raw, " \t")) + 1, Reason: reason}))
+			}
+			if ok || !conds.active() {
+				if err := fn(st); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if target, ok := includeDirective(line); ok && opts.Includes {
+			st.include = target
+			if err := fn(st); err != nil {
+				return err
+			}
+			continue
+		}
+		if strings.TrimSpace(line) == "" || cfg.isComment(line) {
+			if err := fn(st); err != nil {
+				return err
+			}
+			continue
+		}
+		st.section = section
+		if name, ok := sectionHeader(line); ok && cfg.Sections {
+			section = name
+			st.section = name
+			if err := fn(st); err != nil {
+				return err
+			}
+			continue
+		}
+		indent := len(raw) - len(strings.TrimLeft(raw, " \t"))
+		if rest, ok := cutExport(line); ok && cfg.ExportPrefix {
+			indent += len(line) - len(rest)
+			line = rest
+		}
+		invalidKey := func(key string, i int) error {
+			perr := &ParseError{
+				File:   envPath,
+				Line:   lineNo,
+				Col:    indent + i + 1,
+				Reason: fmt.Sprintf("invalid character %q in key", key[i]),
+			}
+			if !opts.Strict {
+				return joinErrors(append(problems, perr))
+			}
+			problems = append(problems, perr)
+			return nil
+		}
+		eq := strings.IndexAny(line, cfg.Separators)
+		if eq < 0 && cfg.rejectInvalidKeys {
+			if i := cfg.keyCheck()(line); i >= 0 {
+				if err := invalidKey(line, i); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if eq < 0 && cfg.InheritBareKeys && cfg.keyCheck()(line) < 0 {
+			if val, ok := os.LookupEnv(line); ok {
+				st.prefix = raw[:indent]
+				st.key = line
+				st.value = val
+				st.quote = '\''
+			}
+			if err := fn(st); err != nil {
+				return err
+			}
+			continue
+		}
+		if eq <= 0 {
+			if opts.Strict {
+				reason := "missing '=' after key"
+				if eq == 0 {
+					reason = "empty key"
+				}
+				problems = append(problems, &ParseError{File: envPath, Line: lineNo, Col: indent + 1, Reason: reason})
+				continue
+			}
+			if err := fn(st); err != nil {
+				return err
+			}
+			continue
+		}
+		key := line[:eq]
+		if cfg.TrimSpace {
+			key = strings.TrimSpace(key)
+		}
+		if opts.Strict || cfg.rejectInvalidKeys {
+			if i := cfg.keyCheck()(key); i >= 0 {
+				if err := invalidKey(key, i); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		rawVal := line[eq+1:]
+		val := rawVal
+		if cfg.TrimSpace {
+			val = strings.TrimSpace(rawVal)
+		}
+
+		st.valueCol = indent + len(line) - len(val) + 1
+		var quote byte
+		unescapeFn := cfg.Unescape
+		if opts.Escapes && unescapeFn == nil {
+			unescapeFn = unescape
+		}
+		escapes := unescapeFn != nil && strings.HasPrefix(val, `"`)
+		if cfg.Quotes && isQuote(val) {
+			end := closingQuote(val[1:], val[0], escapes) + 1
+			switch {
+			case end == 0:
+				// Opening quote without a closing one: the value continues on the
+				// following lines until the matching quote.
+				quote = val[0]
+				var b strings.Builder
+				b.WriteString(val[1:])
+				closed := false
+				for scanner.Scan() {
+					lineNo++
+					next := scanner.Text()
+					st.raw += "\n" + next
+					b.WriteByte('\n')
+					if i := closingQuote(next, quote, escapes); i >= 0 {
+						b.WriteString(next[:i])
+						if rest := next[i+1:]; isInlineComment(rest) {
+							st.comment = strings.TrimRight(rest, " \t")
+						}
+						closed = true
+						break
+					}
+					b.WriteString(next)
+				}
+				if !closed {
+					if err := scanner.Err(); err != nil {
+						return readErr(err)
+					}
+					return joinErrors(append(problems, &ParseError{
+						File:   envPath,
+						Line:   st.line,
+						Col:    st.valueCol,
+						Key:    key,
+						Reason: fmt.Sprintf("unterminated quoted value for %s", key),
+					}))
+				}
+				val = b.String()
+			case isInlineComment(val[end+1:]):
+				quote = val[0]
+				st.comment = val[end+1:]
+				val = val[1:end]
+			case val[len(val)-1] == val[0]:
+				quote = val[0]
+				val = val[1 : len(val)-1]
+			}
+		} else if cfg.InlineComments {
+			if i := inlineComment(rawVal); i >= 0 {
+				st.comment = rawVal[len(strings.TrimRight(rawVal[:i], " \t")):]
+				rawVal = rawVal[:i]
+			}
+			val = rawVal
+			if cfg.TrimSpace {
+				val = strings.TrimSpace(rawVal)
+			}
+		}
+
+		if escapes && quote == '"' {
+			val = unescapeFn(val)
+		}
+
+		st.prefix = raw[:indent]
+		st.key = key
+		if section != "" {
+			st.key = section + "_" + NormalizeUpperSnake(key)
+			st.rawKey = key
+		}
+		st.value = val
+		st.quote = quote
+		if err := fn(st); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return readErr(err)
+	}
+	if conds != nil && len(conds.stack) > 0 {
+		problems = append(problems, &ParseError{File: envPath, Line: lineNo, Col: 1, Reason: "missing #endif"})
+	}
+	return joinErrors(problems)
+}
+
+// parseFile appends the key/value statements of r to raws, applying the
+// duplicate key policy. In strict mode duplicates are reported along with the
+// other problems of the file.
+func parseFile(ctx context.Context, opts Options, r io.Reader, envPath string, raws *[]rawEntry) error {
+	return parseFileStack(ctx, opts, r, envPath, raws, nil)
+}
+
+// parseFileStack is parseFile for a file included from the files in stack.
+func parseFileStack(ctx context.Context, opts Options, r io.Reader, envPath string, raws *[]rawEntry, stack []string) error {
+	if opts.MaxFileSize > 0 {
+		r = &sizeLimitReader{r: r, max: opts.MaxFileSize}
+	}
+	if opts.Encoding != EncodingUTF8 {
+		decoded, err := decode(r, opts.Encoding)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", envPath, err)
+		}
+		r = decoded
+	}
+	if opts.Vault && strings.HasSuffix(envPath, ".vault") {
+		plain, err := openVault(ctx, opts, r, envPath)
+		if err != nil {
+			return err
+		}
+		r = plain
+	}
+	seen := make(map[string]int)
+	var problems []error
+	scan := opts.formatOf(envPath).scanner()
+	err := scan(opts, r, envPath, func(st statement) error {
+		if st.include != "" {
+			return includeFile(ctx, opts, envPath, st, raws, append(slices.Clip(stack), envPath))
+		}
+		if st.key != "" && opts.KeyNormalizer != nil {
+			st.key = opts.KeyNormalizer(st.key)
+		}
+		if st.key == "" {
+			return nil
+		}
+		if first, ok := seen[st.key]; ok {
+			switch opts.Duplicates {
+			case DuplicateFirst:
+				return nil
+			case DuplicateError:
+				perr := &ParseError{
+					File:   envPath,
+					Line:   st.line,
+					Col:    len(st.prefix) + 1,
+					Key:    st.key,
+					Reason: fmt.Sprintf("duplicate key %s, first defined on line %d", st.key, first),
+				}
+				if !opts.Strict {
+					return perr
+				}
+				problems = append(problems, perr)
+				return nil
+			case DuplicateWarn:
+				opts.Logger.Warn("duplicate key", "key", st.key, "path", envPath, "line", st.line, "first", first)
+			}
+		} else {
+			seen[st.key] = st.line
+		}
+		if opts.Decryption && strings.HasPrefix(st.value, EncryptedPrefix) {
+			plain, err := opts.decryptValue(ctx, envPath, st.value)
+			if err != nil {
+				perr := &ParseError{File: envPath, Line: st.line, Col: st.valueCol, Key: st.key, Reason: fmt.Sprintf("decrypt %s: %v", st.key, err)}
+				if !opts.Strict {
+					return perr
+				}
+				problems = append(problems, perr)
+				return nil
+			}
+			st.value = plain
+		}
+		*raws = append(*raws, rawEntry{statement: st, file: envPath})
+		return nil
+	})
+	if err != nil || len(problems) > 0 {
+		return joinErrors(append([]error{err}, problems...))
+	}
+	return nil
+}
+
+// joinErrors is like errors.Join but returns a single error as is, so that
+// callers checking for a concrete type keep working when there is only one
+// problem.
+func joinErrors(errs []error) error {
+	errs = slices.DeleteFunc(errs, func(err error) bool { return err == nil })
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+// inlineComment returns the index of a '#' that starts a trailing comment in
+// an unquoted value, or -1. Only a '#' preceded by whitespace counts, so
+// values like "a#b" are kept intact.
+func inlineComment(val string) int {
+	for i := 1; i < len(val); i++ {
+		if val[i] == '#' && (val[i-1] == ' ' || val[i-1] == '\t') {
+			return i
+		}
+	}
+	return -1
+}
+
+// isInlineComment reports whether rest, the text after a closing quote, is
+// empty or a trailing comment.
+func isInlineComment(rest string) bool {
+	trimmed := strings.TrimLeft(rest, " \t")
+	return trimmed == "" || (trimmed[0] == '#' && len(trimmed) < len(rest))
+}
+
+// sectionHeader returns the upper snake case name of an INI "[section]"
+// line.
+func sectionHeader(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if len(line) < 2 || line[0] != '[' || line[len(line)-1] != ']' {
+		return "", false
+	}
+	return NormalizeUpperSnake(line[1 : len(line)-1]), true
+}
+
+// cutExport strips a leading shell "export" keyword so that files can be
+// sourced by a shell and parsed by this package alike.
+func cutExport(line string) (string, bool) {
+	rest, ok := strings.CutPrefix(line, "export")
+	if !ok || rest == "" || (rest[0] != ' ' && rest[0] != '\t') {
+		return line, false
+	}
+	return strings.TrimLeft(rest, " \t"), true
+}
+
+// invalidKeyChar returns the index of the first byte in key that is not
+// allowed in strict mode, or -1. Keys consist of letters, digits, '_', '.'
+// and '-' and do not start with a digit.
+func invalidKeyChar(key string) int {
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c == '_', c == '.', c == '-', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' && i > 0:
+		default:
+			return i
+		}
+	}
+	return -1
+}
+
+func isQuote(val string) bool {
+	return val != "" && (val[0] == '"' || val[0] == '\'')
+}
+
+// closingQuote returns the index of the first quote in s, or -1. When escapes
+// is set, quotes preceded by a backslash are skipped.
+func closingQuote(s string, quote byte, escapes bool) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case escapes && s[i] == '\\':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// unescape interprets backslash escapes of a double-quoted value. Unknown
+// sequences are kept verbatim so that "\$" still reaches expansion.
+func unescape(s string) string {
+	if !strings.ContainsRune(s, '\\') {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case '"':
+			b.WriteByte('"')
+		case '\\':
+			b.WriteByte('\\')
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// lineReader reads lines like bufio.Scanner, without its 64KB limit on line
+// length. Lines longer than max bytes, when max is positive, stop reading
+// with a *ParseError.
+type lineReader struct {
+	r      *bufio.Reader
+	name   string
+	max    int
+	lineNo int
+	line   string
+	err    error
+}
+
+func newLineReader(r io.Reader, name string, max int) *lineReader {
+	return &lineReader{r: bufio.NewReader(r), name: name, max: max}
+}
+
+// Scan advances to the next line, reporting whether there is one. Line
+// endings, including a "\r" in front of "\n", are dropped.
+func (l *lineReader) Scan() bool {
+	if l.err != nil {
+		return false
+	}
+
+	var b []byte
+	for {
+		chunk, err := l.r.ReadSlice('\n')
+		b = append(b, chunk...)
+		if l.max > 0 && len(bytes.TrimSuffix(bytes.TrimSuffix(b, []byte("\n")), []byte("\r"))) > l.max {
+			l.err = &ParseError{
+				File:   l.name,
+				Line:   l.lineNo + 1,
+				Col:    l.max + 1,
+				Reason: fmt.Sprintf("line exceeds maximum length of %d bytes", l.max),
+			}
+			return false
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if err != nil && err != io.EOF {
+			l.err = err
+			return false
+		}
+		if len(b) == 0 {
+			return false
+		}
+		break
+	}
+
+	l.lineNo++
+	b = bytes.TrimSuffix(b, []byte("\n"))
+	b = bytes.TrimSuffix(b, []byte("\r"))
+	l.line = string(b)
+	return true
+}
+
+// Text returns the current line.
+func (l *lineReader) Text() string {
+	return l.line
+}
+
+// Err returns the first error other than io.EOF.
+func (l *lineReader) Err() error {
+	return l.err
+}
+
+// sizeLimitReader fails with ErrLimitExceeded once r yields more than max
+// bytes.
+type sizeLimitReader struct {
+	r    io.Reader
+	max  int64
+	read int64
+}
+
+func (l *sizeLimitReader) Read(p []byte) (int, error) {
+	// Read one byte past the limit to tell an exact fit from an overflow.
+	if left := l.max - l.read + 1; int64(len(p)) > left {
+		p = p[:left]
+	}
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.max {
+		return 0, fmt.Errorf("%w: file is larger than %d bytes", ErrLimitExceeded, l.max)
+	}
+	return n, err
+}
@@ -0,0 +1,304 @@
+package dotenv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_parseReader(t *testing.T) {
+	t.Run("parses reader content", func(t *testing.T) {
+		env, err := ParseReader(strings.NewReader("A=1\nB='two'\n"))
+		assertNoError(t, err)
+		assertEqual(t, env["A"], "1")
+		assertEqual(t, env["B"], "two")
+	})
+
+	t.Run("applies parser options", func(t *testing.T) {
+		env, err := ParseString("A=1\nB=${A}2\n", WithExpand(true))
+		assertNoError(t, err)
+		assertEqual(t, env["B"], "12")
+
+		_, err = ParseString("A=1\nBROKEN\n", WithStrict())
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("expected *ParseError, got %v", err)
+		}
+		assertEqual(t, perr.File, "<string>")
+		assertEqual(t, perr.Line, 2)
+	})
+}
+
+func Test_parseFile(t *testing.T) {
+	t.Run("multiline quoted values", func(t *testing.T) {
+		fs := fstest.MapFS{
+			".env": &fstest.MapFile{Data: []byte(`BEFORE=1
+KEY="-----BEGIN KEY-----
+abc
+  def
+-----END KEY-----"
+SINGLE='a
+b'
+AFTER=2
+`)},
+		}
+		env, err := Parse(WithFs(fs))
+		assertNoError(t, err)
+		assertEqual(t, env["KEY"], "-----BEGIN KEY-----\nabc\n  def\n-----END KEY-----")
+		assertEqual(t, env["SINGLE"], "a\nb")
+		assertEqual(t, env["BEFORE"], "1")
+		assertEqual(t, env["AFTER"], "2")
+	})
+
+	t.Run("unterminated quote is an error", func(t *testing.T) {
+		fs := fstest.MapFS{
+			".env": &fstest.MapFile{Data: []byte(`A=1
+KEY="never closed
+B=2
+`)},
+		}
+		_, err := Parse(WithFs(fs))
+		if err == nil || !strings.Contains(err.Error(), ".env:2") {
+			t.Fatalf("expected unterminated quote error at line 2, got %v", err)
+		}
+	})
+
+	t.Run("escapes in double quotes", func(t *testing.T) {
+		fs := fstest.MapFS{
+			".env": &fstest.MapFile{Data: []byte(`MSG="line1\nline2\tend"
+QUOTED="say \"hi\" \\o/"
+SINGLE='line1\nline2'
+BARE=line1\nline2
+MULTI="first \"
+second"
+`)},
+		}
+		env, err := Parse(WithFs(fs), WithEscapes(true))
+		assertNoError(t, err)
+		assertEqual(t, env["MSG"], "line1\nline2\tend")
+		assertEqual(t, env["QUOTED"], `say "hi" \o/`)
+		assertEqual(t, env["SINGLE"], `line1\nline2`)
+		assertEqual(t, env["BARE"], `line1\nline2`)
+		assertEqual(t, env["MULTI"], "first \"\nsecond")
+
+		env, err = Parse(WithFs(fstest.MapFS{
+			".env": &fstest.MapFile{Data: []byte(`MSG="line1\nline2"` + "\n")},
+		}))
+		assertNoError(t, err)
+		assertEqual(t, env["MSG"], `line1\nline2`)
+	})
+
+	t.Run("strict mode reports malformed lines", func(t *testing.T) {
+		cases := []struct {
+			name string
+			data string
+			want ParseError
+		}{
+			{"missing separator", "A=1\n  JUST_A_KEY\n", ParseError{File: ".env", Line: 2, Col: 3, Reason: "missing '=' after key"}},
+			{"empty key", "=1\n", ParseError{File: ".env", Line: 1, Col: 1, Reason: "empty key"}},
+			{"invalid key", "\n\nMY KEY=1\n", ParseError{File: ".env", Line: 3, Col: 3, Reason: `invalid character ' ' in key`}},
+			{"unterminated quote", "A=1\nKEY=\"open\n", ParseError{File: ".env", Line: 2, Col: 5, Key: "KEY", Reason: "unterminated quoted value for KEY"}},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				fs := fstest.MapFS{".env": &fstest.MapFile{Data: []byte(tc.data)}}
+				_, err := Parse(WithFs(fs), WithStrict())
+				var perr *ParseError
+				if !errors.As(err, &perr) {
+					t.Fatalf("expected *ParseError, got %v", err)
+				}
+				assertEqual(t, *perr, tc.want)
+			})
+		}
+	})
+
+	t.Run("non-strict mode skips malformed lines", func(t *testing.T) {
+		fs := fstest.MapFS{".env": &fstest.MapFile{Data: []byte("JUST_A_KEY\n=1\nA=1\n")}}
+		env, err := Parse(WithFs(fs))
+		assertNoError(t, err)
+		assertEqual(t, len(env), 1)
+	})
+
+	t.Run("export prefix", func(t *testing.T) {
+		fs := fstest.MapFS{".env": &fstest.MapFile{Data: []byte("export A=1\nexport\tB='two'\nexport=3\nexporter=4\n")}}
+		env, err := Parse(WithFs(fs), WithStrict())
+		assertNoError(t, err)
+		assertEqual(t, env["A"], "1")
+		assertEqual(t, env["B"], "two")
+		assertEqual(t, env["export"], "3")
+		assertEqual(t, env["exporter"], "4")
+	})
+
+	t.Run("inline comments", func(t *testing.T) {
+		fs := fstest.MapFS{".env": &fstest.MapFile{Data: []byte(`PORT=8080 # local dev
+TAB=1	# tab before comment
+HASH=a#b
+ONLY= # nothing here
+LEADING=#kept
+DQ="a # b" # comment
+SQ='#x'	# comment
+ODD="a"b"
+`)}}
+		env, err := Parse(WithFs(fs))
+		assertNoError(t, err)
+		assertEqual(t, env["PORT"], "8080")
+		assertEqual(t, env["TAB"], "1")
+		assertEqual(t, env["HASH"], "a#b")
+		assertEqual(t, env["ONLY"], "")
+		assertEqual(t, env["LEADING"], "#kept")
+		assertEqual(t, env["DQ"], "a # b")
+		assertEqual(t, env["SQ"], "#x")
+		assertEqual(t, env["ODD"], `a"b`)
+	})
+}
+
+func Test_duplicatePolicy(t *testing.T) {
+	const content = "A=1\nB=x\nA=2\n"
+
+	t.Run("last wins by default", func(t *testing.T) {
+		env, err := ParseString(content)
+		assertNoError(t, err)
+		assertEqual(t, env["A"], "2")
+	})
+
+	t.Run("first wins", func(t *testing.T) {
+		env, err := ParseString(content, WithDuplicatePolicy(DuplicateFirst))
+		assertNoError(t, err)
+		assertEqual(t, env["A"], "1")
+	})
+
+	t.Run("error points at the duplicate", func(t *testing.T) {
+		_, err := ParseString(content, WithDuplicatePolicy(DuplicateError))
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("expected *ParseError, got %v", err)
+		}
+		assertEqual(t, perr.Line, 3)
+		assertEqual(t, perr.Reason, "duplicate key A, first defined on line 1")
+	})
+
+	t.Run("warn logs and last wins", func(t *testing.T) {
+		lg := &testLogger{}
+		env, err := ParseString(content, WithDuplicatePolicy(DuplicateWarn), WithLogger(lg))
+		assertNoError(t, err)
+		assertEqual(t, env["A"], "2")
+		if !strings.Contains(lg.String(), "duplicate key") {
+			t.Fatalf("expected duplicate warning, got %q", lg.String())
+		}
+	})
+
+	t.Run("keys repeated across files are not duplicates", func(t *testing.T) {
+		fs := fstest.MapFS{
+			"a/.env": &fstest.MapFile{Data: []byte("A=1\n")},
+			"b/.env": &fstest.MapFile{Data: []byte("A=2\n")},
+		}
+		env, err := Parse(WithPaths("a", "b"), WithFs(fs), WithDuplicatePolicy(DuplicateError))
+		assertNoError(t, err)
+		assertEqual(t, env["A"], "2")
+	})
+}
+
+func Test_strictAggregation(t *testing.T) {
+	fs := fstest.MapFS{
+		"a/.env": &fstest.MapFile{Data: []byte("A=1\nBROKEN\nA=2\n=x\n")},
+		"b/.env": &fstest.MapFile{Data: []byte("1BAD=x\nB@D=y\n")},
+	}
+
+	_, err := Parse(
+		WithPaths("a", "b"),
+		WithFs(fs),
+		WithStrict(),
+		WithDuplicatePolicy(DuplicateError),
+		WithRequired("MISSING"),
+	)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("expected joined error, got %T", err)
+	}
+	var lines []string
+	for _, e := range joined.Unwrap() {
+		lines = append(lines, strings.Split(e.Error(), "\n")...)
+	}
+	assertEqual(t, strings.Join(lines, "\n"), strings.Join([]string{
+		"a/.env:2:1: missing '=' after key",
+		"a/.env:4:1: empty key",
+		"a/.env:3:1: duplicate key A, first defined on line 1",
+		"b/.env:1:1: invalid character '1' in key",
+		"b/.env:2:2: invalid character '@' in key",
+		"missing required keys: MISSING",
+	}, "\n"))
+
+	var missing *MissingKeysError
+	if !errors.As(err, &missing) {
+		t.Fatalf("expected *MissingKeysError in %v", err)
+	}
+}
+
+func Test_strictSingleProblem(t *testing.T) {
+	_, err := ParseString("A=1\nBROKEN\n", WithStrict())
+	if _, ok := err.(*ParseError); !ok {
+		t.Fatalf("expected bare *ParseError, got %T", err)
+	}
+}
+
+func Test_longLines(t *testing.T) {
+	long := strings.Repeat("a", 200<<10)
+
+	t.Run("reads lines beyond 64KB", func(t *testing.T) {
+		env, err := ParseString("CERT=" + long + "\r\nB=2")
+		assertNoError(t, err)
+		assertEqual(t, env["CERT"], long)
+		assertEqual(t, env["B"], "2")
+	})
+
+	t.Run("max line length guard", func(t *testing.T) {
+		_, err := ParseString("A=1\nCERT="+long+"\n", WithMaxLineLength(1024))
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("expected *ParseError, got %v", err)
+		}
+		assertEqual(t, perr.Line, 2)
+		assertEqual(t, perr.Reason, "line exceeds maximum length of 1024 bytes")
+	})
+
+	t.Run("systemd dialect guard", func(t *testing.T) {
+		_, err := ParseString("CERT="+long+"\n", WithDialect(DialectSystemd), WithMaxLineLength(1024))
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("expected *ParseError, got %v", err)
+		}
+		assertEqual(t, perr.Col, 1025)
+	})
+}
+
+func Test_limits(t *testing.T) {
+	t.Run("max file size", func(t *testing.T) {
+		_, err := ParseString("A=1\nB=2\n", WithMaxFileSize(8))
+		assertNoError(t, err)
+
+		_, err = ParseString("A=1\nB=2\nC=3\n", WithMaxFileSize(8))
+		if !errors.Is(err, ErrLimitExceeded) {
+			t.Fatalf("expected ErrLimitExceeded, got %v", err)
+		}
+		assertEqual(t, err.Error(), "read <string>: limit exceeded: file is larger than 8 bytes")
+	})
+
+	t.Run("max keys", func(t *testing.T) {
+		fs := fstest.MapFS{
+			"a/.env": &fstest.MapFile{Data: []byte("A=1\nB=2\n")},
+			"b/.env": &fstest.MapFile{Data: []byte("A=3\nC=4\n")},
+		}
+		_, err := Parse(WithPaths("a", "b"), WithFs(fs), WithMaxKeys(3))
+		assertNoError(t, err)
+
+		_, err = Parse(WithPaths("a", "b"), WithFs(fs), WithMaxKeys(2))
+		if !errors.Is(err, ErrLimitExceeded) {
+			t.Fatalf("expected ErrLimitExceeded, got %v", err)
+		}
+		assertEqual(t, err.Error(), "limit exceeded: more than 2 keys, C at b/.env:2")
+	})
+}
@@ -0,0 +1,54 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"runtime"
+)
+
+// ErrInsecurePermissions is wrapped by the errors of WithStrictPermissions.
+var ErrInsecurePermissions = errors.New("insecure file permissions")
+
+// DefaultPermissionMask flags files readable by their group or by others.
+const DefaultPermissionMask fs.FileMode = 0o044
+
+// WithStrictPermissions refuses to read files whose permissions have any
+// bit of the permission mask set, by default files readable by their group
+// or by everyone. Without it such files are read and a warning is logged.
+// The check is skipped on Windows and for file systems that don't report
+// permissions.
+func WithStrictPermissions() Option {
+	return func(o *Options) {
+		o.StrictPermissions = true
+	}
+}
+
+// WithPermissionMask replaces DefaultPermissionMask, for example with 0o077
+// to flag any access by group or others. A zero mask disables the check.
+func WithPermissionMask(mask fs.FileMode) Option {
+	return func(o *Options) {
+		o.PermissionMask = mask
+	}
+}
+
+// checkPermissions warns about or, under WithStrictPermissions, rejects f
+// when its permissions are too open.
+func checkPermissions(opts Options, f fs.File, envPath string) error {
+	if opts.PermissionMask == 0 || runtime.GOOS == "windows" {
+		return nil
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", envPath, err)
+	}
+	perm := info.Mode().Perm()
+	if perm&opts.PermissionMask == 0 {
+		return nil
+	}
+	if opts.StrictPermissions {
+		return fmt.Errorf("%w: %s has mode %04o, want no bits of %04o", ErrInsecurePermissions, envPath, perm, opts.PermissionMask)
+	}
+	opts.Logger.Warn("file permissions are too open", "path", envPath, "mode", fmt.Sprintf("%04o", perm))
+	return nil
+}
@@ -0,0 +1,51 @@
+package dotenv
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_permissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permissions are not checked on windows")
+	}
+	fs := fstest.MapFS{
+		".env":        &fstest.MapFile{Data: []byte("A=1\n"), Mode: 0o644},
+		".env.secret": &fstest.MapFile{Data: []byte("B=2\n"), Mode: 0o600},
+		".env.group":  &fstest.MapFile{Data: []byte("C=3\n"), Mode: 0o620},
+	}
+
+	t.Run("warns by default", func(t *testing.T) {
+		logger := &testLogger{}
+		env, err := Parse(WithFs(fs), WithLogger(logger))
+		assertNoError(t, err)
+		assertEqual(t, env["A"], "1")
+		if !strings.Contains(logger.String(), "file permissions are too open") {
+			t.Fatalf("missing warning in %q", logger.String())
+		}
+	})
+
+	t.Run("strict refuses", func(t *testing.T) {
+		_, err := Parse(WithFs(fs), WithStrictPermissions())
+		if !errors.Is(err, ErrInsecurePermissions) {
+			t.Fatalf("want ErrInsecurePermissions, got %v", err)
+		}
+		env, err := Parse(WithFs(fs), WithPaths(".env.secret"), WithStrictPermissions())
+		assertNoError(t, err)
+		assertEqual(t, env["B"], "2")
+	})
+
+	t.Run("mask", func(t *testing.T) {
+		_, err := Parse(WithFs(fs), WithPaths(".env.group"), WithStrictPermissions())
+		assertNoError(t, err)
+		_, err = Parse(WithFs(fs), WithPaths(".env.group"), WithStrictPermissions(), WithPermissionMask(0o077))
+		if !errors.Is(err, ErrInsecurePermissions) {
+			t.Fatalf("want ErrInsecurePermissions, got %v", err)
+		}
+		_, err = Parse(WithFs(fs), WithStrictPermissions(), WithPermissionMask(0))
+		assertNoError(t, err)
+	})
+}
@@ -0,0 +1,126 @@
+package dotenv
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// scanProperties reads a Java .properties file and calls fn with a statement
+// per key, see FormatProperties.
+func scanProperties(opts Options, r io.Reader, envPath string, fn func(statement) error) error {
+	scanner := newLineReader(r, envPath, opts.MaxLineLength)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		start := lineNo
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		// A line ending in an odd number of backslashes continues on the
+		// next one, whose leading whitespace is dropped.
+		for continues(line) && scanner.Scan() {
+			lineNo++
+			line = line[:len(line)-1] + strings.TrimLeft(scanner.Text(), " \t\f")
+		}
+		if continues(line) {
+			line = line[:len(line)-1]
+		}
+
+		key, value := splitProperty(line)
+		k, err := unescapeProperty(key)
+		if err != nil {
+			return &ParseError{File: envPath, Line: start, Col: 1, Reason: err.Error()}
+		}
+		v, err := unescapeProperty(value)
+		if err != nil {
+			return &ParseError{File: envPath, Line: start, Col: len(line) - len(value) + 1, Reason: err.Error()}
+		}
+		if err := fn(statement{line: start, key: k, value: v, quote: '\''}); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if _, ok := err.(*ParseError); ok {
+			return err
+		}
+		return fmt.Errorf("read %s: %w", envPath, err)
+	}
+	return nil
+}
+
+func continues(line string) bool {
+	n := len(line) - len(strings.TrimRight(line, `\`))
+	return n%2 == 1
+}
+
+// splitProperty splits a logical line at the first unescaped '=', ':' or
+// whitespace, dropping the separator and the whitespace around it.
+func splitProperty(line string) (key, value string) {
+	i := 0
+	for ; i < len(line); i++ {
+		c := line[i]
+		if c == '\\' {
+			i++
+			continue
+		}
+		if c == '=' || c == ':' || c == ' ' || c == '\t' || c == '\f' {
+			break
+		}
+	}
+	key = line[:min(i, len(line))]
+	rest := strings.TrimLeft(line[min(i, len(line)):], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	return key, rest
+}
+
+// unescapeProperty interprets the escapes of the properties format: \t, \n,
+// \r, \f and \uXXXX; any other escaped character stands for itself.
+func unescapeProperty(s string) (string, error) {
+	if !strings.ContainsRune(s, '\\') {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch c := s[i]; c {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("invalid unicode escape %q", s[i-1:])
+			}
+			code, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("invalid unicode escape %q", s[i-1:i+5])
+			}
+			i += 4
+			// Characters outside the BMP are written as surrogate pairs.
+			if r := rune(code); 0xD800 <= r && r < 0xDC00 && strings.HasPrefix(s[i+1:], `\u`) && i+7 <= len(s) {
+				if low, err := strconv.ParseUint(s[i+3:i+7], 16, 16); err == nil && 0xDC00 <= low && low < 0xE000 {
+					b.WriteRune((r-0xD800)<<10 + (rune(low) - 0xDC00) + 0x10000)
+					i += 6
+					continue
+				}
+			}
+			b.WriteRune(rune(code))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
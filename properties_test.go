@@ -0,0 +1,42 @@
+package dotenv
+
+import "testing"
+
+func Test_formatProperties(t *testing.T) {
+	const content = `# comment
+! also a comment
+db.url = jdbc:postgresql://localhost/app
+db.user: admin
+greeting   Hello
+fruits = apple, \
+         banana, \
+         cherry
+path=C:\\temp
+key\ with\ spaces=v
+unicode=caf\u00e9 \ud83d\ude00
+tabbed=a\tb
+empty
+`
+	env, err := ParseString(content, WithFormat(FormatProperties))
+	assertNoError(t, err)
+
+	want := map[string]string{
+		"db.url":          "jdbc:postgresql://localhost/app",
+		"db.user":         "admin",
+		"greeting":        "Hello",
+		"fruits":          "apple, banana, cherry",
+		"path":            `C:\temp`,
+		"key with spaces": "v",
+		"unicode":         "café 😀",
+		"tabbed":          "a\tb",
+		"empty":           "",
+	}
+	assertEqual(t, len(env), len(want))
+	for k, v := range want {
+		assertEqual(t, env[k], v)
+	}
+
+	env, err = ParseString("db.url=x\n", WithFormat(FormatProperties), WithKeyNormalizer(NormalizeUpperSnake))
+	assertNoError(t, err)
+	assertEqual(t, env["DB_URL"], "x")
+}
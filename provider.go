@@ -0,0 +1,127 @@
+package dotenv
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"sync"
+)
+
+// Provider supplies variables from somewhere other than a dotenv file, such
+// as a configuration service or a secret store. The name Source is taken by
+// the provenance records returned by Sources.
+type Provider interface {
+	Fetch(ctx context.Context) (map[string]string, error)
+}
+
+// ProviderFunc adapts a function to the Provider interface.
+type ProviderFunc func(ctx context.Context) (map[string]string, error)
+
+// Fetch calls f(ctx).
+func (f ProviderFunc) Fetch(ctx context.Context) (map[string]string, error) {
+	return f(ctx)
+}
+
+// WithProviders adds providers that are fetched after the dotenv paths, in
+// order, each overriding the values before it. Values from providers are
+// taken literally and never expanded. Reports and Sources name a provider
+// by its String method if it has one, or by its type otherwise.
+//
+// To interleave providers with files, register a URL scheme with
+// RegisterProvider and list URLs among the paths instead.
+func WithProviders(providers ...Provider) Option {
+	return func(o *Options) {
+		o.Providers = append(o.Providers, providers...)
+	}
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]func(url string) (Provider, error){}
+)
+
+// RegisterProvider makes open handle paths of the form scheme://..., so that
+// they can be passed to WithPaths next to files and merged in order:
+//
+//	dotenv.Load(dotenv.WithPaths(".env", "ssm:///myapp/prod"))
+//
+// open receives the full URL. It panics when scheme is already registered or
+// open is nil, like database/sql.Register.
+func RegisterProvider(scheme string, open func(url string) (Provider, error)) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	if open == nil {
+		panic("dotenv: RegisterProvider open is nil")
+	}
+	if _, dup := providers[scheme]; dup {
+		panic("dotenv: RegisterProvider called twice for scheme " + scheme)
+	}
+	providers[scheme] = open
+}
+
+var urlScheme = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]*)://`)
+
+// providerFor returns the provider for p if it is a URL. URLs with a scheme
+// nobody registered are an error rather than a missing file.
+func providerFor(p string) (Provider, bool, error) {
+	m := urlScheme.FindStringSubmatch(p)
+	if m == nil {
+		return nil, false, nil
+	}
+	providersMu.RLock()
+	open, ok := providers[m[1]]
+	providersMu.RUnlock()
+	if !ok {
+		return nil, true, fmt.Errorf("no provider registered for scheme %q in %s", m[1], redactURL(p))
+	}
+	provider, err := open(p)
+	if err != nil {
+		return nil, true, fmt.Errorf("open provider %s: %w", redactURL(p), err)
+	}
+	return provider, true, nil
+}
+
+// redactURL drops the userinfo and query of a URL path, which often carry
+// credentials, so that it can be reported and logged.
+func redactURL(p string) string {
+	u, err := url.Parse(p)
+	if err != nil {
+		return urlScheme.FindString(p) + "<invalid>"
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.ForceQuery = false
+	return u.String()
+}
+
+// fetchProvider appends the values of provider to raws, named name.
+func fetchProvider(ctx context.Context, opts Options, name string, provider Provider, raws *[]rawEntry) error {
+	values, err := provider.Fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch %s: %w", name, err)
+	}
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		value := values[key]
+		if opts.KeyNormalizer != nil {
+			if key = opts.KeyNormalizer(key); key == "" {
+				continue
+			}
+		}
+		*raws = append(*raws, rawEntry{
+			statement: statement{key: key, value: value, quote: '\''},
+			file:      name,
+		})
+	}
+	return nil
+}
+
+// providerName names p in reports.
+func providerName(p Provider) string {
+	if s, ok := p.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", p)
+}
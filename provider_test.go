@@ -0,0 +1,78 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+// registerMemtest keeps the registration from panicking with -count > 1.
+var registerMemtest sync.Once
+
+type staticProvider map[string]string
+
+func (p staticProvider) Fetch(context.Context) (map[string]string, error) { return p, nil }
+func (p staticProvider) String() string                                   { return "static" }
+
+func Test_providers(t *testing.T) {
+	fs := fstest.MapFS{
+		".env":       &fstest.MapFile{Data: []byte("A=file\nB=file\nC=file\n")},
+		".env.local": &fstest.MapFile{Data: []byte("C=local\n")},
+	}
+	registerMemtest.Do(func() {
+		RegisterProvider("memtest", func(url string) (Provider, error) {
+			return staticProvider{"B": "registered", "C": "registered", "URL": url}, nil
+		})
+	})
+
+	t.Run("paths and providers merge in order", func(t *testing.T) {
+		env, err := Parse(
+			WithFs(fs),
+			WithPaths(".env", "memtest://config", ".env.local"),
+			WithProviders(staticProvider{"A": "provider ${B}"}),
+			WithExpand(true),
+		)
+		assertNoError(t, err)
+		assertEqual(t, env["A"], "provider ${B}")
+		assertEqual(t, env["B"], "registered")
+		assertEqual(t, env["C"], "local")
+		assertEqual(t, env["URL"], "memtest://config")
+	})
+
+	t.Run("reports name providers", func(t *testing.T) {
+		report, err := LoadReport(WithFs(fs), WithPaths(".env"), WithProviders(staticProvider{"A": "x"}),
+			WithSetter(func(string, string) error { return nil }))
+		assertNoError(t, err)
+		assertEqual(t, report.Loaded[0].File, "static")
+		assertEqual(t, strings.Join(report.Loaded[0].Shadowed, ","), ".env")
+	})
+
+	t.Run("fetch errors", func(t *testing.T) {
+		errDown := errors.New("down")
+		_, err := Parse(WithFs(fs), WithProviders(ProviderFunc(func(context.Context) (map[string]string, error) {
+			return nil, errDown
+		})))
+		if !errors.Is(err, errDown) {
+			t.Fatalf("expected errDown, got %v", err)
+		}
+	})
+
+	t.Run("unknown scheme", func(t *testing.T) {
+		_, err := Parse(WithFs(fs), WithPaths("nope://x"))
+		if err == nil || !strings.Contains(err.Error(), `no provider registered for scheme "nope"`) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("duplicate registration panics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected panic")
+			}
+		}()
+		RegisterProvider("memtest", func(string) (Provider, error) { return nil, nil })
+	})
+}
@@ -0,0 +1,72 @@
+package dotenv
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Masked replaces values flagged by LikelySecret.
+const Masked = "****"
+
+// secretKeyParts mark keys that usually hold secrets.
+var secretKeyParts = []string{"TOKEN", "SECRET", "PASSWORD", "PASSWD", "PASSPHRASE", "API_KEY", "APIKEY", "PRIVATE_KEY", "CREDENTIAL", "AUTH"}
+
+// WithSecretRedaction adds values to the KeyReports of LoadReport, masking
+// those that LikelySecret flags as Masked and setting Secret. Without it
+// reports carry no values at all. The package never logs values either way.
+func WithSecretRedaction() Option {
+	return func(o *Options) {
+		o.SecretRedaction = true
+	}
+}
+
+// LikelySecret reports whether a variable probably holds a secret, judging
+// by a key containing a word such as TOKEN, SECRET or PASSWORD, or by a
+// value that looks random: long, without spaces, mixing letters and digits
+// and with a high Shannon entropy. It is a heuristic for keeping secrets out
+// of logs, not a guarantee.
+func LikelySecret(key, value string) bool {
+	upper := strings.ToUpper(key)
+	for _, part := range secretKeyParts {
+		if strings.Contains(upper, part) {
+			return true
+		}
+	}
+	return looksRandom(value)
+}
+
+// Redact returns Masked when LikelySecret flags the variable, and value
+// otherwise.
+func Redact(key, value string) string {
+	if LikelySecret(key, value) {
+		return Masked
+	}
+	return value
+}
+
+func looksRandom(value string) bool {
+	if len(value) < 20 || strings.ContainsFunc(value, unicode.IsSpace) {
+		return false
+	}
+	if !strings.ContainsFunc(value, unicode.IsLetter) || !strings.ContainsFunc(value, unicode.IsDigit) {
+		return false
+	}
+	return entropy(value) >= 4
+}
+
+// entropy returns the Shannon entropy of s in bits per byte.
+func entropy(s string) float64 {
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+	var h float64
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / float64(len(s))
+			h -= p * math.Log2(p)
+		}
+	}
+	return h
+}
@@ -0,0 +1,106 @@
+package dotenv
+
+import (
+	"maps"
+	"os"
+	"slices"
+	"sync"
+)
+
+// ownedKey is a variable Load changed in the process environment.
+type ownedKey struct {
+	source Source
+	// value is what Load set, or unset is true if it removed the variable.
+	value string
+	unset bool
+	// original is the state before Load first changed the variable.
+	original priorValue
+}
+
+// current reports whether the process environment still holds what Load
+// left there, that is whether nobody else changed the variable since.
+func (o *ownedKey) current() bool {
+	val, ok := os.LookupEnv(o.original.key)
+	if o.unset {
+		return !ok
+	}
+	return ok && val == o.value
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]*ownedKey{}
+)
+
+// OwnedKeys returns the sorted keys of the variables that Load set or unset
+// in the process environment and that were not reverted since. Loads using
+// WithSetter do not own anything. Features that undo loads, such as
+// WithSync, only ever touch these variables.
+func OwnedKeys() []string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return slices.Sorted(maps.Keys(registry))
+}
+
+// own records that Load changed key from prior to value, or unset it. The
+// original state is kept from the first change unless someone else changed
+// the variable in between.
+func own(key string, src Source, value string, unset bool, prior priorValue) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	o, ok := registry[key]
+	if !ok || prior != (priorValue{key: key, value: o.value, set: !o.unset}) {
+		o = &ownedKey{original: prior}
+		registry[key] = o
+	}
+	o.source, o.value, o.unset = src, value, unset
+}
+
+// restored records that key was reverted to p by restorePriors.
+func restored(p priorValue) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	o, ok := registry[p.key]
+	if !ok {
+		return
+	}
+	if p == o.original {
+		delete(registry, p.key)
+		return
+	}
+	o.value, o.unset = p.value, !p.set
+}
+
+// release reverts the variables in keys that are still as Load left them to
+// their original state and forgets them. It returns the states it replaced.
+func release(keys []string) ([]priorValue, error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	var priors []priorValue
+	for _, key := range keys {
+		o, ok := registry[key]
+		if !ok {
+			continue
+		}
+		delete(registry, key)
+		if !o.current() {
+			continue
+		}
+		priors = append(priors, priorValue{key: key, value: o.value, set: !o.unset})
+		if err := setPrior(o.original); err != nil {
+			return priors, err
+		}
+	}
+	return priors, nil
+}
+
+// Unload reverts everything earlier loads applied: each variable listed by
+// OwnedKeys gets back the value it had before it was first loaded, or is
+// unset if it had none. Variables changed by someone else since they were
+// loaded are left as they are. Afterwards OwnedKeys and Sources are empty.
+func Unload() error {
+	applyMu.Lock()
+	defer applyMu.Unlock()
+	_, err := release(OwnedKeys())
+	return err
+}
@@ -0,0 +1,106 @@
+package dotenv
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_ownedKeys(t *testing.T) {
+	t.Setenv("OWN_PRESET", "original")
+	t.Setenv("OWN_NEW", "")
+	os.Unsetenv("OWN_NEW")
+	fs := fstest.MapFS{
+		"a.env": &fstest.MapFile{Data: []byte("OWN_PRESET=1\nOWN_NEW=1\n")},
+		"b.env": &fstest.MapFile{Data: []byte("OWN_PRESET=2\n")},
+		"c.env": &fstest.MapFile{Data: []byte("OTHER=1\n")},
+	}
+	t.Cleanup(func() { os.Unsetenv("OTHER") })
+
+	assertNoError(t, Load(WithFs(fs), WithPaths("a.env")))
+	owned := OwnedKeys()
+	if !slices.Contains(owned, "OWN_PRESET") || !slices.Contains(owned, "OWN_NEW") {
+		t.Fatalf("expected loaded keys to be owned, got %v", owned)
+	}
+
+	t.Run("keeps the value from before the first load", func(t *testing.T) {
+		assertNoError(t, Load(WithFs(fs), WithPaths("b.env"), WithSync()))
+		assertEqual(t, os.Getenv("OWN_PRESET"), "2")
+		_, ok := os.LookupEnv("OWN_NEW")
+		assertEqual(t, ok, false)
+
+		assertNoError(t, Load(WithFs(fs), WithPaths("c.env"), WithSync()))
+		assertEqual(t, os.Getenv("OWN_PRESET"), "original")
+		if slices.Contains(OwnedKeys(), "OWN_PRESET") {
+			t.Fatalf("OWN_PRESET still owned after revert: %v", OwnedKeys())
+		}
+	})
+
+	t.Run("leaves variables changed by others", func(t *testing.T) {
+		assertNoError(t, Load(WithFs(fs), WithPaths("a.env")))
+		os.Setenv("OWN_PRESET", "changed")
+		report, err := LoadReport(WithFs(fs), WithPaths("c.env"), WithSync())
+		assertNoError(t, err)
+		for _, kr := range report.Removed {
+			if kr.Key == "OWN_PRESET" {
+				t.Fatal("OWN_PRESET reported as removed")
+			}
+		}
+		assertEqual(t, os.Getenv("OWN_PRESET"), "changed")
+	})
+}
+
+func Test_unload(t *testing.T) {
+	t.Setenv("UNLOAD_PRESET", "original")
+	t.Setenv("UNLOAD_NEW", "")
+	t.Setenv("UNLOAD_CHANGED", "")
+	os.Unsetenv("UNLOAD_NEW")
+	fs := fstest.MapFS{
+		".env": &fstest.MapFile{Data: []byte("UNLOAD_PRESET=1\nUNLOAD_NEW=1\nUNLOAD_CHANGED=1\n")},
+	}
+
+	assertNoError(t, Load(WithFs(fs)))
+	assertNoError(t, Load(WithFs(fs)))
+	os.Setenv("UNLOAD_CHANGED", "mine")
+
+	assertNoError(t, Unload())
+	assertEqual(t, os.Getenv("UNLOAD_PRESET"), "original")
+	_, ok := os.LookupEnv("UNLOAD_NEW")
+	assertEqual(t, ok, false)
+	assertEqual(t, os.Getenv("UNLOAD_CHANGED"), "mine")
+	assertEqual(t, len(OwnedKeys()), 0)
+	assertEqual(t, len(Sources()), 0)
+}
+
+func Test_customSetterOwnsNothing(t *testing.T) {
+	t.Setenv("OWN_SYNCED", "")
+	os.Unsetenv("OWN_SYNCED")
+	fs := fstest.MapFS{
+		"a.env": &fstest.MapFile{Data: []byte("OWN_SYNCED=1\n")},
+		"b.env": &fstest.MapFile{Data: []byte("OWN_MAPPED=1\nOWN_MAPPED_EMPTY=\n")},
+	}
+	assertNoError(t, Load(WithFs(fs), WithPaths("a.env")))
+
+	target := map[string]string{}
+	setter := WithSetter(func(key, value string) error {
+		target[key] = value
+		return nil
+	})
+	unsetter := WithUnsetter(func(key string) error {
+		delete(target, key)
+		return nil
+	})
+	assertNoError(t, Load(WithFs(fs), WithPaths("b.env"), setter, unsetter, WithEmptyValues(EmptyUnset), WithSync()))
+	assertEqual(t, target["OWN_MAPPED"], "1")
+
+	owned := OwnedKeys()
+	if slices.Contains(owned, "OWN_MAPPED") || slices.Contains(owned, "OWN_MAPPED_EMPTY") {
+		t.Fatalf("keys loaded through a custom setter are owned: %v", owned)
+	}
+	if src, ok := Sources()["OWN_MAPPED"]; ok {
+		t.Fatalf("OWN_MAPPED listed in Sources: %+v", src)
+	}
+	assertEqual(t, os.Getenv("OWN_SYNCED"), "1")
+	assertNoError(t, Unload())
+}
@@ -0,0 +1,262 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Report describes the outcome of LoadReport. Keys are sorted. It encodes
+// to JSON, so that services can log it once at startup for auditing;
+// durations are encoded in nanoseconds.
+type Report struct {
+	// Loaded lists keys that were exported to the process environment.
+	Loaded []KeyReport `json:"loaded"`
+	// Skipped lists keys that were left untouched because they were already
+	// set and WithNoOverride was used.
+	Skipped []KeyReport `json:"skipped"`
+	// Unset lists keys removed from the environment by empty assignments
+	// under EmptyUnset.
+	Unset []KeyReport `json:"unset"`
+	// Removed lists keys set by an earlier load that were unset because
+	// the files no longer define them, under WithSync.
+	Removed []KeyReport `json:"removed,omitempty"`
+	// Files lists the files, key directories and providers that were
+	// considered, in the order they were read.
+	Files []FileReport `json:"files"`
+	// Warnings lists the warnings logged while loading.
+	Warnings []Warning `json:"warnings"`
+	// Duration is the time loading took.
+	Duration time.Duration `json:"duration"`
+	// DryRun reports that WithDryRun was used and the lists above describe
+	// changes that were not applied.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// KeyReport describes a single key handled by LoadReport.
+type KeyReport struct {
+	Key string `json:"key"`
+	// File and Line locate the definition the final value came from.
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Shadowed lists earlier files that also defined the key and whose
+	// values were overridden by File.
+	Shadowed []string `json:"shadowed,omitempty"`
+	// OverrodeEnv reports that the key was already present in the process
+	// environment and its value was replaced.
+	OverrodeEnv bool `json:"overrode_env,omitempty"`
+	// Value is the loaded value, or Masked when Secret is set. It is only
+	// filled in with WithSecretRedaction.
+	Value string `json:"value,omitempty"`
+	// Secret reports that LikelySecret flagged the key; only set with
+	// WithSecretRedaction.
+	Secret bool `json:"secret,omitempty"`
+}
+
+// File statuses reported in FileReport.
+const (
+	FileLoaded  = "loaded"
+	FileMissing = "missing"
+	FileFailed  = "failed"
+)
+
+// FileReport describes a source considered by LoadReport: a dotenv file,
+// a key directory or a provider.
+type FileReport struct {
+	Path string `json:"path"`
+	// Status is FileLoaded, FileMissing or FileFailed.
+	Status string `json:"status"`
+	// Keys counts the assignments read, including ones overridden later.
+	Keys     int           `json:"keys"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Warning is a warning logged while loading, with its key-value pairs.
+type Warning struct {
+	Message string         `json:"message"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+}
+
+// LoadReport works like Load and additionally reports which keys were
+// loaded, which were skipped and which replaced earlier values, along with
+// the files read and the warnings logged. When loading fails the report
+// describes what happened up to the failure.
+func LoadReport(userOptions ...Option) (*Report, error) {
+	report, _, err := load(context.Background(), userOptions)
+	return report, err
+}
+
+// load parses and exports the configured files. It returns the values that
+// were in place before, in the order they were replaced, so that the caller
+// can undo the changes. On failure nothing is exported.
+func load(ctx context.Context, userOptions []Option) (*Report, []priorValue, error) {
+	start := time.Now()
+	opts, err := buildOptions(userOptions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can export .env file with these options: %w", err)
+	}
+
+	report := &Report{DryRun: opts.DryRun}
+	defer func() { report.Duration = time.Since(start) }()
+	opts.Logger = &reportLogger{Logger: opts.Logger, report: report}
+	opts.fileReports = &report.Files
+
+	env, err := parse(ctx, opts)
+	if err != nil {
+		return report, nil, err
+	}
+	applyMu.Lock()
+	defer applyMu.Unlock()
+	priors, err := apply(opts, env, report)
+	return report, priors, err
+}
+
+// applyMu serializes changes to the process environment, so that concurrent
+// loads, restores and unloads each take effect as a whole and never
+// interleave their values.
+var applyMu sync.Mutex
+
+// apply exports env to the process environment, describing the changes in
+// report, and returns the values that were replaced. env is fully parsed and
+// validated by then, so only the setter can fail; a failure reverts the
+// variables exported so far, leaving the process environment as it was.
+// Callers hold applyMu.
+func apply(opts Options, env entries, report *Report) (priors []priorValue, err error) {
+	defer func() {
+		if err == nil {
+			return
+		}
+		if rerr := restorePriors(priors); rerr != nil {
+			err = errors.Join(err, fmt.Errorf("reverting: %w", rerr))
+		}
+		priors = nil
+		report.Loaded, report.Unset, report.Removed = nil, nil, nil
+	}()
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		e := env[key]
+		kr := KeyReport{Key: key, File: e.file, Line: e.line, Shadowed: e.shadowed}
+		if opts.SecretRedaction && !e.unset {
+			kr.Secret = LikelySecret(key, e.value)
+			kr.Value = e.value
+			if kr.Secret {
+				kr.Value = Masked
+			}
+		}
+		prev, isSet := os.LookupEnv(key)
+		if isSet {
+			if opts.NoOverride {
+				opts.Logger.Info("variable already set; skipping", "key", key)
+				report.Skipped = append(report.Skipped, kr)
+				continue
+			}
+			kr.OverrodeEnv = true
+		}
+		if opts.DryRun {
+			if e.unset {
+				report.Unset = append(report.Unset, kr)
+			} else {
+				report.Loaded = append(report.Loaded, kr)
+			}
+			continue
+		}
+		if e.unset {
+			unset := opts.unsetter()
+			if unset == nil {
+				return priors, fmt.Errorf("unsetenv %s: WithSetter is used without WithUnsetter", key)
+			}
+			if err := unset(key); err != nil {
+				return priors, fmt.Errorf("unsetenv %s: %w", key, err)
+			}
+			prior := priorValue{key: key, value: prev, set: isSet}
+			priors = append(priors, prior)
+			if !opts.customSetter {
+				own(key, Source{File: e.file, Line: e.line}, "", true, prior)
+			}
+			report.Unset = append(report.Unset, kr)
+			opts.logKey("variable unset", kr, "")
+			continue
+		}
+		if err := opts.Setter(key, e.value); err != nil {
+			return priors, fmt.Errorf("setenv %s: %w", key, err)
+		}
+		prior := priorValue{key: key, value: prev, set: isSet}
+		priors = append(priors, prior)
+		report.Loaded = append(report.Loaded, kr)
+		opts.logKey("variable set", kr, e.value)
+		if !opts.customSetter {
+			own(key, Source{File: e.file, Line: e.line}, e.value, false, prior)
+		}
+	}
+	if opts.Sync && !opts.customSetter {
+		removed, err := syncRemoved(opts, env, report)
+		priors = append(priors, removed...)
+		if err != nil {
+			return priors, err
+		}
+	}
+	if opts.Metrics != nil && !opts.DryRun {
+		opts.Metrics.KeysSet(len(report.Loaded))
+		opts.Metrics.KeysSkipped(len(report.Skipped))
+	}
+	return priors, nil
+}
+
+// reportLogger records warnings in a Report before passing them on.
+type reportLogger struct {
+	Logger
+	mu     sync.Mutex
+	report *Report
+}
+
+func (l *reportLogger) Debug(msg string, args ...any) {
+	if d, ok := l.Logger.(DebugLogger); ok {
+		d.Debug(msg, args...)
+	}
+}
+
+func (l *reportLogger) Warn(msg string, args ...any) {
+	w := Warning{Message: msg}
+	for i := 0; i+1 < len(args); i += 2 {
+		if w.Attrs == nil {
+			w.Attrs = map[string]any{}
+		}
+		v := args[i+1]
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		w.Attrs[fmt.Sprint(args[i])] = v
+	}
+	l.mu.Lock()
+	l.report.Warnings = append(l.report.Warnings, w)
+	l.mu.Unlock()
+	l.Logger.Warn(msg, args...)
+}
+
+// reportFile records a source considered by parse, if a report is being
+// collected.
+func (o Options) reportFile(path string, keys int, d time.Duration, err error) {
+	if o.fileReports == nil {
+		return
+	}
+	fr := FileReport{Path: path, Status: FileLoaded, Keys: keys, Duration: d}
+	if err != nil {
+		fr.Status, fr.Error = FileFailed, err.Error()
+	}
+	*o.fileReports = append(*o.fileReports, fr)
+}
+
+// WithDryRun makes Load and LoadReport resolve, parse, expand and validate
+// everything as usual but change nothing: no variable is set or unset and
+// Sources is not updated. LoadReport then describes what a real load would
+// do, which suits a --check-config flag.
+func WithDryRun() Option {
+	return func(o *Options) {
+		o.DryRun = true
+	}
+}
@@ -0,0 +1,123 @@
+package dotenv
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_loadReport(t *testing.T) {
+	fs := fstest.MapFS{
+		"a/.env": &fstest.MapFile{Data: []byte(`SHARED=a
+PRESET=a
+`)},
+		"b/.env": &fstest.MapFile{Data: []byte(`SHARED=b
+FRESH=b
+`)},
+	}
+
+	t.Run("reports loaded and overridden keys", func(t *testing.T) {
+		os.Unsetenv("SHARED")
+		os.Unsetenv("FRESH")
+		t.Setenv("PRESET", "ci")
+
+		report, err := LoadReport(WithPaths("a", "b"), WithFs(fs))
+		assertNoError(t, err)
+		assertEqual(t, len(report.Loaded), 3)
+		assertEqual(t, len(report.Skipped), 0)
+
+		fresh, preset, shared := report.Loaded[0], report.Loaded[1], report.Loaded[2]
+		assertEqual(t, fresh.Key, "FRESH")
+		assertEqual(t, fresh.File, "b/.env")
+		assertEqual(t, preset.Key, "PRESET")
+		assertEqual(t, preset.OverrodeEnv, true)
+		assertEqual(t, shared.Key, "SHARED")
+		assertEqual(t, shared.File, "b/.env")
+		assertEqual(t, strings.Join(shared.Shadowed, ","), "a/.env")
+	})
+
+	t.Run("reports skipped keys with no override", func(t *testing.T) {
+		os.Unsetenv("SHARED")
+		os.Unsetenv("FRESH")
+		t.Setenv("PRESET", "ci")
+
+		report, err := LoadReport(WithPaths("a", "b"), WithFs(fs), WithNoOverride())
+		assertNoError(t, err)
+		assertEqual(t, len(report.Loaded), 2)
+		assertEqual(t, len(report.Skipped), 1)
+		assertEqual(t, report.Skipped[0].Key, "PRESET")
+		assertEqual(t, os.Getenv("PRESET"), "ci")
+	})
+}
+
+func Test_loadReportJSON(t *testing.T) {
+	fs := fstest.MapFS{
+		".env":       &fstest.MapFile{Data: []byte("JR_A=1\nJR_B=2\nJR_A=3\n")},
+		".env.local": &fstest.MapFile{Data: []byte("JR_C=3\n")},
+	}
+	noop := WithSetter(func(string, string) error { return nil })
+
+	report, err := LoadReport(WithFs(fs), WithPaths(".env", "missing", ".env.local"), noop, WithDuplicatePolicy(DuplicateWarn))
+	assertNoError(t, err)
+	assertEqual(t, len(report.Files), 3)
+	assertEqual(t, report.Files[0].Path, ".env")
+	assertEqual(t, report.Files[0].Status, FileLoaded)
+	assertEqual(t, report.Files[0].Keys, 3)
+	assertEqual(t, report.Files[1].Status, FileMissing)
+	assertEqual(t, report.Files[2].Path, ".env.local")
+	assertEqual(t, len(report.Warnings), 2)
+	assertEqual(t, report.Warnings[0].Message, "duplicate key")
+	assertEqual(t, report.Warnings[0].Attrs["key"], "JR_A")
+	assertEqual(t, report.Warnings[1].Message, "path not found")
+	if report.Duration <= 0 {
+		t.Fatal("duration not recorded")
+	}
+
+	data, err := json.Marshal(report)
+	assertNoError(t, err)
+	var decoded map[string]any
+	assertNoError(t, json.Unmarshal(data, &decoded))
+	for _, field := range []string{"loaded", "skipped", "unset", "files", "warnings", "duration"} {
+		if _, ok := decoded[field]; !ok {
+			t.Errorf("missing %q in %s", field, data)
+		}
+	}
+
+	t.Run("partial report on failure", func(t *testing.T) {
+		fs := fstest.MapFS{".env": &fstest.MapFile{Data: []byte("JR_A=\"open\n")}}
+		report, err := LoadReport(WithFs(fs), noop, WithStrict())
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		assertEqual(t, len(report.Files), 1)
+		assertEqual(t, report.Files[0].Status, FileFailed)
+	})
+}
+
+func Test_dryRun(t *testing.T) {
+	fs := fstest.MapFS{".env": &fstest.MapFile{Data: []byte("DRY_A=1\nDRY_B=\n")}}
+	t.Setenv("DRY_B", "kept")
+	os.Unsetenv("DRY_A")
+
+	report, err := LoadReport(WithFs(fs), WithDryRun(), WithEmptyValues(EmptyUnset))
+	assertNoError(t, err)
+	assertEqual(t, report.DryRun, true)
+	assertEqual(t, len(report.Loaded), 1)
+	assertEqual(t, report.Loaded[0].Key, "DRY_A")
+	assertEqual(t, len(report.Unset), 1)
+	assertEqual(t, report.Unset[0].OverrodeEnv, true)
+
+	_, ok := os.LookupEnv("DRY_A")
+	assertEqual(t, ok, false)
+	assertEqual(t, os.Getenv("DRY_B"), "kept")
+	_, recorded := Sources()["DRY_A"]
+	assertEqual(t, recorded, false)
+
+	_, err = LoadReport(WithFs(fs), WithDryRun(), WithRequired("DRY_MISSING"))
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("validation should still run, got %v", err)
+	}
+}
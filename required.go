@@ -0,0 +1,39 @@
+package dotenv
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MissingKeysError reports required keys that were defined neither in the
+// parsed files nor in the process environment. It matches ErrValidation.
+type MissingKeysError struct {
+	Keys []string
+}
+
+func (e *MissingKeysError) Error() string {
+	return fmt.Sprintf("missing required keys: %s", strings.Join(e.Keys, ", "))
+}
+
+// Is reports whether target is ErrValidation.
+func (e *MissingKeysError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+func checkRequired(required []string, env entries) error {
+	var missing []string
+	for _, key := range required {
+		if _, ok := env.lookup(key); ok {
+			continue
+		}
+		if _, ok := os.LookupEnv(key); ok {
+			continue
+		}
+		missing = append(missing, key)
+	}
+	if len(missing) > 0 {
+		return &MissingKeysError{Keys: missing}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_required(t *testing.T) {
+	fs := fstest.MapFS{
+		"a/.env": &fstest.MapFile{Data: []byte("REQ_A=1\n")},
+		"b/.env": &fstest.MapFile{Data: []byte("REQ_B=\n")},
+	}
+
+	t.Run("passes when defined in files or environment", func(t *testing.T) {
+		t.Setenv("REQ_ENV", "ci")
+		_, err := Parse(WithPaths("a", "b"), WithFs(fs), WithRequired("REQ_A", "REQ_B"), WithRequired("REQ_ENV"))
+		assertNoError(t, err)
+	})
+
+	t.Run("reports all missing keys", func(t *testing.T) {
+		os.Unsetenv("REQ_X")
+		os.Unsetenv("REQ_Y")
+		os.Unsetenv("REQ_A")
+		err := Load(WithPaths("a"), WithFs(fs), WithRequired("REQ_X", "REQ_A", "REQ_Y"))
+		var missing *MissingKeysError
+		if !errors.As(err, &missing) {
+			t.Fatalf("expected *MissingKeysError, got %v", err)
+		}
+		assertEqual(t, err.Error(), "missing required keys: REQ_X, REQ_Y")
+
+		_, set := os.LookupEnv("REQ_A")
+		assertEqual(t, set, false)
+	})
+}
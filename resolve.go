@@ -0,0 +1,159 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// rawEntry is a parsed statement that is not expanded yet.
+type rawEntry struct {
+	statement
+	file string
+}
+
+// resolveEntries expands raws in order and merges them into entries, later
+// definitions overriding earlier ones. Command substitutions run with ctx.
+func resolveEntries(ctx context.Context, opts Options, raws []rawEntry) (entries, error) {
+	r := &resolver{
+		ctx:      ctx,
+		opts:     opts,
+		expand:   opts.Expand || opts.parserConfig().Expand,
+		raws:     raws,
+		resolved: make([]*string, len(raws)),
+	}
+
+	env := make(entries)
+	for i, raw := range raws {
+		empty := raw.value == "" && raw.quote == 0
+		if empty && opts.EmptyValues == EmptySkip {
+			continue
+		}
+
+		val, err := r.resolve(i)
+		if err != nil {
+			return nil, err
+		}
+
+		e := entry{value: val, file: raw.file, line: raw.line}
+		e.unset = empty && opts.EmptyValues == EmptyUnset
+		if prev, ok := env[raw.key]; ok {
+			opts.debug("key overridden", "key", raw.key, "file", raw.file, "line", raw.line, "previous_file", prev.file, "previous_line", prev.line)
+			e.shadowed = prev.shadowed
+			if prev.file != raw.file {
+				e.shadowed = append(slices.Clip(e.shadowed), prev.file)
+			}
+		} else if opts.MaxKeys > 0 && len(env) == opts.MaxKeys {
+			return nil, fmt.Errorf("%w: more than %d keys, %s at %s:%d", ErrLimitExceeded, opts.MaxKeys, raw.key, raw.file, raw.line)
+		}
+		env[raw.key] = e
+	}
+	return env, nil
+}
+
+// resolver expands raw entries on demand so that forward references can be
+// followed, detecting cycles along the way.
+type resolver struct {
+	ctx      context.Context
+	opts     Options
+	expand   bool
+	raws     []rawEntry
+	resolved []*string
+	// stack holds the indexes of entries currently being expanded.
+	stack []int
+}
+
+func (r *resolver) resolve(i int) (string, error) {
+	if v := r.resolved[i]; v != nil {
+		return *v, nil
+	}
+
+	raw := r.raws[i]
+	val := raw.value
+	if r.expand && raw.quote != '\'' {
+		if slices.Contains(r.stack, i) {
+			return "", r.errorAt(i, "expansion cycle: "+r.cycle(i))
+		}
+		if len(r.stack) >= r.opts.ExpandDepth {
+			return "", r.errorAt(i, fmt.Sprintf("expansion depth limit %d exceeded", r.opts.ExpandDepth))
+		}
+
+		r.stack = append(r.stack, i)
+		x := expander{
+			lookup: func(name string) (string, bool, error) {
+				return r.lookup(name, i)
+			},
+		}
+		if r.opts.CommandRunner != nil {
+			x.run = r.runCommand
+		}
+		expanded, err := x.expand(val)
+		r.stack = r.stack[:len(r.stack)-1]
+		if err != nil {
+			var perr *ParseError
+			if errors.As(err, &perr) {
+				return "", err
+			}
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return "", fmt.Errorf("%s:%d: %s: %w", raw.file, raw.line, raw.key, err)
+			}
+			return "", r.errorAt(i, err.Error())
+		}
+		val = expanded
+	}
+
+	r.resolved[i] = &val
+	return val, nil
+}
+
+// runCommand executes a command substitution, limited to WithTimeout like a
+// source read but not retried.
+func (r *resolver) runCommand(command string) (string, error) {
+	if err := r.ctx.Err(); err != nil {
+		return "", err
+	}
+	return tryOnce(r.ctx, r.opts.Timeout, func(ctx context.Context) (string, error) {
+		return r.opts.CommandRunner(ctx, command)
+	})
+}
+
+// lookup resolves a reference made by entry at. The closest earlier
+// definition wins; otherwise the first later one is followed, except for
+// self references, which fall back to the process environment.
+func (r *resolver) lookup(name string, at int) (string, bool, error) {
+	for j := at - 1; j >= 0; j-- {
+		if r.raws[j].key == name {
+			val, err := r.resolve(j)
+			return val, true, err
+		}
+	}
+	if name != r.raws[at].key {
+		for j := at + 1; j < len(r.raws); j++ {
+			if r.raws[j].key == name {
+				val, err := r.resolve(j)
+				return val, true, err
+			}
+		}
+	}
+	val, ok := os.LookupEnv(name)
+	return val, ok, nil
+}
+
+// cycle formats the keys on the stack from the first occurrence of i.
+func (r *resolver) cycle(i int) string {
+	start := slices.Index(r.stack, i)
+	keys := make([]string, 0, len(r.stack)-start+1)
+	for _, j := range r.stack[start:] {
+		keys = append(keys, r.raws[j].key)
+	}
+	keys = append(keys, r.raws[i].key)
+	return strings.Join(keys, " -> ")
+}
+
+func (r *resolver) errorAt(i int, reason string) *ParseError {
+	raw := r.raws[i]
+	return &ParseError{File: raw.file, Line: raw.line, Col: raw.valueCol, Key: raw.key, Reason: reason}
+}
@@ -0,0 +1,56 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// priorValue is the state of a variable before Load replaced it.
+type priorValue struct {
+	key   string
+	value string
+	set   bool
+}
+
+// LoadWithRestore works like Load and additionally returns a function that
+// undoes its changes to the process environment: variables that were not set
+// before are unset and replaced ones get their previous values back. When
+// loading fails nothing is exported and restore does nothing. Restoring
+// always targets the process environment, so it is meant for use without
+// WithSetter.
+func LoadWithRestore(userOptions ...Option) (restore func() error, err error) {
+	_, priors, err := load(context.Background(), userOptions)
+	return func() error {
+		applyMu.Lock()
+		defer applyMu.Unlock()
+		return restorePriors(priors)
+	}, err
+}
+
+func restorePriors(priors []priorValue) error {
+	var errs []error
+	for i := len(priors) - 1; i >= 0; i-- {
+		if err := setPrior(priors[i]); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		restored(priors[i])
+	}
+	return errors.Join(errs...)
+}
+
+// setPrior puts p back into the process environment.
+func setPrior(p priorValue) error {
+	if p.set {
+		if err := os.Setenv(p.key, p.value); err != nil {
+			return fmt.Errorf("setenv %s: %w", p.key, err)
+		}
+		return nil
+	}
+	if err := os.Unsetenv(p.key); err != nil {
+		return fmt.Errorf("unsetenv %s: %w", p.key, err)
+	}
+	return nil
+}
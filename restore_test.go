@@ -0,0 +1,95 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_loadWithRestore(t *testing.T) {
+	fs := fstest.MapFS{
+		".env": &fstest.MapFile{Data: []byte("RESTORE_NEW=file\nRESTORE_SET=file\n")},
+	}
+	t.Setenv("RESTORE_SET", "before")
+	os.Unsetenv("RESTORE_NEW")
+
+	restore, err := LoadWithRestore(WithFs(fs))
+	assertNoError(t, err)
+	assertEqual(t, os.Getenv("RESTORE_NEW"), "file")
+	assertEqual(t, os.Getenv("RESTORE_SET"), "file")
+
+	assertNoError(t, restore())
+	_, set := os.LookupEnv("RESTORE_NEW")
+	assertEqual(t, set, false)
+	assertEqual(t, os.Getenv("RESTORE_SET"), "before")
+}
+
+func Test_concurrentLoads(t *testing.T) {
+	t.Setenv("CONC_A", "")
+	t.Setenv("CONC_B", "")
+	fs := fstest.MapFS{
+		"one.env": &fstest.MapFile{Data: []byte("CONC_A=one\nCONC_B=one\n")},
+		"two.env": &fstest.MapFile{Data: []byte("CONC_A=two\nCONC_B=two\n")},
+	}
+	setter := func(key, value string) error {
+		runtime.Gosched()
+		return os.Setenv(key, value)
+	}
+
+	for range 50 {
+		var wg sync.WaitGroup
+		for _, path := range []string{"one.env", "two.env"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := Load(WithFs(fs), WithPaths(path), WithSetter(setter)); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+		assertEqual(t, os.Getenv("CONC_A"), os.Getenv("CONC_B"))
+	}
+}
+
+func Test_failedLoadLeavesEnvUntouched(t *testing.T) {
+	t.Setenv("ATOMIC_SET", "before")
+	t.Setenv("ATOMIC_NEW", "")
+	os.Unsetenv("ATOMIC_NEW")
+	fs := fstest.MapFS{
+		"a/.env": &fstest.MapFile{Data: []byte("ATOMIC_NEW=a\nATOMIC_SET=a\n")},
+		"b/.env": &fstest.MapFile{Data: []byte("ATOMIC_SET=b\nBROKEN\n")},
+	}
+	assertUntouched := func(t *testing.T) {
+		t.Helper()
+		_, set := os.LookupEnv("ATOMIC_NEW")
+		assertEqual(t, set, false)
+		assertEqual(t, os.Getenv("ATOMIC_SET"), "before")
+	}
+
+	t.Run("parse error in a later file", func(t *testing.T) {
+		err := Load(WithFs(fs), WithPaths("a", "b"), WithStrict())
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		assertUntouched(t)
+	})
+
+	t.Run("setter error", func(t *testing.T) {
+		setter := func(key, value string) error {
+			if key == "ATOMIC_SET" {
+				return errors.New("boom")
+			}
+			return os.Setenv(key, value)
+		}
+		report, err := LoadReport(WithFs(fs), WithPaths("a"), WithSetter(setter))
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		assertUntouched(t)
+		assertEqual(t, len(report.Loaded), 0)
+	})
+}
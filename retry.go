@@ -0,0 +1,92 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"time"
+)
+
+// WithRetry retries reading a source up to n more times when it fails with
+// an error that may be transient, such as an I/O error on a network mount or
+// a failed request to a provider. The delay before the first retry is
+// backoff and doubles with every further attempt. Missing files, parse
+// errors, exceeded limits, insecure permissions and lockfile mismatches are
+// not retried.
+func WithRetry(n int, backoff time.Duration) Option {
+	return func(o *Options) {
+		o.Retries = n
+		o.RetryBackoff = backoff
+	}
+}
+
+// WithTimeout limits every attempt to read a source, from the first stat of
+// a path to the last read, to d. An attempt that runs out of time fails with
+// context.DeadlineExceeded and is retried under WithRetry. Each $(command)
+// substitution is limited to d as well, without retries.
+func WithTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = d
+	}
+}
+
+// retry calls fn until it succeeds, fails permanently or runs out of
+// attempts, as configured by WithRetry and WithTimeout.
+func retry[T any](ctx context.Context, opts Options, name string, fn func(context.Context) (T, error)) (T, error) {
+	delay := opts.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		v, err := tryOnce(ctx, opts.Timeout, fn)
+		if err == nil || attempt > opts.Retries || ctx.Err() != nil || !retryable(err) {
+			return v, err
+		}
+
+		opts.Logger.Warn("reading source failed; retrying", "source", name, "attempt", attempt, "delay", delay, "error", err)
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return v, ctx.Err()
+		case <-t.C:
+		}
+		delay *= 2
+	}
+}
+
+// tryOnce calls fn with a context limited to timeout. File systems don't
+// observe contexts, so fn runs in its own goroutine to keep a hung stat or
+// read from blocking past the deadline; its result is then abandoned.
+func tryOnce[T any](ctx context.Context, timeout time.Duration, fn func(context.Context) (T, error)) (T, error) {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		v   T
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		v, err := fn(ctx)
+		done <- result{v, err}
+	}()
+	select {
+	case r := <-done:
+		return r.v, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// retryable reports whether err may go away when the source is read again.
+func retryable(err error) bool {
+	var perr *ParseError
+	return !errors.As(err, &perr) &&
+		!errors.Is(err, fs.ErrNotExist) &&
+		!errors.Is(err, fs.ErrPermission) &&
+		!errors.Is(err, ErrLimitExceeded) &&
+		!errors.Is(err, ErrInsecurePermissions) &&
+		!errors.Is(err, ErrLockMismatch)
+}
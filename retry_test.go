@@ -0,0 +1,102 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// flakyFS fails the first failures opens and stats with an I/O error.
+type flakyFS struct {
+	fs.FS
+	failures int
+	calls    int
+}
+
+var errFlaky = errors.New("input/output error")
+
+func (f *flakyFS) Open(name string) (fs.File, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: errFlaky}
+	}
+	return f.FS.Open(name)
+}
+
+func Test_retry(t *testing.T) {
+	files := fstest.MapFS{
+		".env":    &fstest.MapFile{Data: []byte("A=1\n")},
+		"bad.env": &fstest.MapFile{Data: []byte("A\n")},
+	}
+
+	t.Run("recovers from transient errors", func(t *testing.T) {
+		flaky := &flakyFS{FS: files, failures: 2}
+		logger := &testLogger{}
+		env, err := Parse(WithFs(flaky), WithPaths(".env"), WithRetry(2, time.Millisecond), WithLogger(logger))
+		assertNoError(t, err)
+		assertEqual(t, env["A"], "1")
+		assertEqual(t, strings.Count(logger.String(), "retrying"), 2)
+	})
+
+	t.Run("gives up after n retries", func(t *testing.T) {
+		flaky := &flakyFS{FS: files, failures: 3}
+		_, err := Parse(WithFs(flaky), WithPaths(".env"), WithRetry(1, time.Millisecond))
+		if !errors.Is(err, errFlaky) {
+			t.Fatalf("want flaky error, got %v", err)
+		}
+		assertEqual(t, flaky.calls, 2)
+	})
+
+	t.Run("permanent errors are not retried", func(t *testing.T) {
+		flaky := &flakyFS{FS: files}
+		_, err := Parse(WithFs(flaky), WithPaths("bad.env"), WithStrict(), WithRetry(3, time.Millisecond))
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("want ParseError, got %v", err)
+		}
+		assertEqual(t, flaky.calls, 2) // stat and open
+	})
+
+	t.Run("providers", func(t *testing.T) {
+		calls := 0
+		provider := ProviderFunc(func(context.Context) (map[string]string, error) {
+			if calls++; calls == 1 {
+				return nil, errors.New("connection reset")
+			}
+			return map[string]string{"B": "2"}, nil
+		})
+		env, err := Parse(WithFs(files), WithProviders(provider), WithRetry(1, 0))
+		assertNoError(t, err)
+		assertEqual(t, env["B"], "2")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		var calls atomic.Int32
+		provider := ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+			if calls.Add(1) == 1 {
+				<-ctx.Done()
+				return nil, nil
+			}
+			return map[string]string{"B": "2"}, nil
+		})
+		env, err := Parse(WithFs(files), WithProviders(provider), WithTimeout(10*time.Millisecond), WithRetry(1, 0))
+		assertNoError(t, err)
+		assertEqual(t, env["B"], "2")
+
+		release := make(chan struct{})
+		defer close(release)
+		hang := ProviderFunc(func(context.Context) (map[string]string, error) {
+			<-release // ignores the context, like a stuck file system call
+			return nil, nil
+		})
+		_, err = Parse(WithFs(files), WithProviders(hang), WithTimeout(10*time.Millisecond))
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("want DeadlineExceeded, got %v", err)
+		}
+	})
+}
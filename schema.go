@@ -0,0 +1,220 @@
+package dotenv
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Schema declares validation rules for keys. Build one with NewSchema and
+// Key, then pass it to LoadAndValidate or WithSchema:
+//
+//	schema := dotenv.NewSchema()
+//	schema.Key("PORT").Required().Range(1, 65535)
+//	schema.Key("LOG_LEVEL").Default("info").OneOf("debug", "info", "warn")
+type Schema struct {
+	rules []*Rule
+}
+
+// NewSchema returns an empty schema.
+func NewSchema() *Schema {
+	return &Schema{}
+}
+
+// Key returns the rule for key, creating it on first use.
+func (s *Schema) Key(key string) *Rule {
+	for _, r := range s.rules {
+		if r.key == key {
+			return r
+		}
+	}
+	r := &Rule{key: key, minLen: -1, maxLen: -1}
+	s.rules = append(s.rules, r)
+	return r
+}
+
+// Rule holds the constraints for a single key. Constraints other than
+// Required only apply when the key has a value.
+type Rule struct {
+	key        string
+	required   bool
+	hasDefault bool
+	def        string
+	enum       []string
+	pattern    *regexp.Regexp
+	minLen     int
+	maxLen     int
+	hasRange   bool
+	min, max   float64
+}
+
+// Required makes a missing key a violation.
+func (r *Rule) Required() *Rule {
+	r.required = true
+	return r
+}
+
+// Default sets the value used when the key is defined nowhere. Defaults are
+// exported by Load like values from files.
+func (r *Rule) Default(value string) *Rule {
+	r.hasDefault = true
+	r.def = value
+	return r
+}
+
+// OneOf restricts the value to the given set.
+func (r *Rule) OneOf(values ...string) *Rule {
+	r.enum = values
+	return r
+}
+
+// Match requires the whole value to match re.
+func (r *Rule) Match(re *regexp.Regexp) *Rule {
+	r.pattern = re
+	return r
+}
+
+// Len bounds the value length in bytes. A negative bound is ignored.
+func (r *Rule) Len(min, max int) *Rule {
+	r.minLen = min
+	r.maxLen = max
+	return r
+}
+
+// Range requires the value to be a number within [min, max].
+func (r *Rule) Range(min, max float64) *Rule {
+	r.hasRange = true
+	r.min = min
+	r.max = max
+	return r
+}
+
+// Violation describes a value that breaks a schema rule. Reason never
+// includes the value itself, so violations are safe to log.
+type Violation struct {
+	Key    string
+	Rule   string
+	Reason string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("%s: %s", v.Key, v.Reason)
+}
+
+// ValidationError collects every schema violation found during a load. It
+// matches ErrValidation.
+type ValidationError struct {
+	Violations []*Violation
+}
+
+// Is reports whether target is ErrValidation.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Error()
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap exposes the individual violations to errors.Is and errors.As.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Violations))
+	for i, v := range e.Violations {
+		errs[i] = v
+	}
+	return errs
+}
+
+// WithSchema validates the parsed values against schema once all paths are
+// parsed and fills in defaults. All violations are reported together in a
+// *ValidationError.
+func WithSchema(schema *Schema) Option {
+	return func(o *Options) {
+		o.Schema = schema
+	}
+}
+
+// LoadAndValidate is Load with WithSchema(schema).
+func LoadAndValidate(schema *Schema, userOptions ...Option) error {
+	return Load(append(userOptions, WithSchema(schema))...)
+}
+
+// applySchema adds defaults for keys defined nowhere and validates the
+// effective values, i.e. what the process would see after Load.
+func applySchema(opts Options, env entries) error {
+	if opts.Schema == nil {
+		return nil
+	}
+
+	var violations []*Violation
+	for _, r := range opts.Schema.rules {
+		val, ok := effectiveValue(opts, env, r.key)
+		if !ok && r.hasDefault {
+			env[r.key] = entry{value: r.def, file: "<default>"}
+			val, ok = r.def, true
+		}
+		if !ok {
+			if r.required {
+				violations = append(violations, &Violation{Key: r.key, Rule: "required", Reason: "is required"})
+			}
+			continue
+		}
+		violations = append(violations, r.check(val)...)
+	}
+
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+func effectiveValue(opts Options, env entries, key string) (string, bool) {
+	if opts.NoOverride {
+		if val, ok := os.LookupEnv(key); ok {
+			return val, true
+		}
+	}
+	if e, ok := env.lookup(key); ok {
+		return e.value, true
+	}
+	return os.LookupEnv(key)
+}
+
+func (r *Rule) check(val string) []*Violation {
+	var violations []*Violation
+	add := func(rule, format string, args ...any) {
+		violations = append(violations, &Violation{Key: r.key, Rule: rule, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	if r.enum != nil && !slices.Contains(r.enum, val) {
+		add("enum", "value is not one of %s", strings.Join(r.enum, ", "))
+	}
+	if r.pattern != nil {
+		if loc := r.pattern.FindStringIndex(val); loc == nil || loc[0] != 0 || loc[1] != len(val) {
+			add("pattern", "value does not match %s", r.pattern)
+		}
+	}
+	if r.minLen >= 0 && len(val) < r.minLen {
+		add("min_length", "length %d is below %d", len(val), r.minLen)
+	}
+	if r.maxLen >= 0 && len(val) > r.maxLen {
+		add("max_length", "length %d is above %d", len(val), r.maxLen)
+	}
+	if r.hasRange {
+		n, err := strconv.ParseFloat(val, 64)
+		switch {
+		case err != nil:
+			add("range", "value is not a number")
+		case n < r.min || n > r.max:
+			add("range", "value is outside [%v, %v]", r.min, r.max)
+		}
+	}
+	return violations
+}
@@ -0,0 +1,67 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_schema(t *testing.T) {
+	newSchema := func() *Schema {
+		s := NewSchema()
+		s.Key("SCHEMA_PORT").Required().Range(1, 65535)
+		s.Key("SCHEMA_LEVEL").Default("info").OneOf("debug", "info", "warn")
+		s.Key("SCHEMA_NAME").Len(3, 8).Match(regexp.MustCompile(`[a-z]+`))
+		s.Key("SCHEMA_TOKEN").Required()
+		return s
+	}
+	unset := func() {
+		for _, k := range []string{"SCHEMA_PORT", "SCHEMA_LEVEL", "SCHEMA_NAME", "SCHEMA_TOKEN"} {
+			os.Unsetenv(k)
+		}
+	}
+
+	t.Run("valid values and defaults", func(t *testing.T) {
+		unset()
+		fs := fstest.MapFS{".env": &fstest.MapFile{Data: []byte("SCHEMA_PORT=8080\nSCHEMA_NAME=api\n")}}
+		t.Setenv("SCHEMA_TOKEN", "from-env")
+
+		err := LoadAndValidate(newSchema(), WithFs(fs))
+		assertNoError(t, err)
+		assertEqual(t, os.Getenv("SCHEMA_LEVEL"), "info")
+		assertEqual(t, os.Getenv("SCHEMA_PORT"), "8080")
+	})
+
+	t.Run("reports every violation", func(t *testing.T) {
+		unset()
+		fs := fstest.MapFS{".env": &fstest.MapFile{Data: []byte("SCHEMA_PORT=99999\nSCHEMA_LEVEL=trace\nSCHEMA_NAME=API-SERVER\n")}}
+
+		_, err := Parse(WithFs(fs), WithSchema(newSchema()))
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected *ValidationError, got %v", err)
+		}
+		var rules []string
+		for _, v := range verr.Violations {
+			rules = append(rules, v.Key+":"+v.Rule)
+		}
+		assertEqual(t, len(rules), 5)
+		want := []string{"SCHEMA_PORT:range", "SCHEMA_LEVEL:enum", "SCHEMA_NAME:pattern", "SCHEMA_NAME:max_length", "SCHEMA_TOKEN:required"}
+		for i := range want {
+			assertEqual(t, rules[i], want[i])
+		}
+
+		var v *Violation
+		if !errors.As(err, &v) {
+			t.Fatalf("expected errors.As to find a *Violation")
+		}
+		for _, secret := range []string{"99999", "trace", "API-SERVER"} {
+			if strings.Contains(err.Error(), secret) {
+				t.Fatalf("value %q leaked into error: %v", secret, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// searchUpFS walks from dir towards the root of fsys and returns the first
+// directory containing one of names. The walk stops after a directory that
+// contains .git. found is false when no such directory exists.
+func searchUpFS(fsys fs.FS, dir string, names []string) (string, bool, error) {
+	for {
+		for _, name := range append(names[:len(names):len(names)], ".git") {
+			_, err := fs.Stat(fsys, path.Join(dir, name))
+			if err == nil {
+				return dir, name != ".git", nil
+			}
+			if !errors.Is(err, fs.ErrNotExist) {
+				return "", false, fmt.Errorf("stat %s: %w", path.Join(dir, name), err)
+			}
+		}
+		if dir == "." {
+			return "", false, nil
+		}
+		dir = path.Dir(dir)
+	}
+}
+
+// searchUpOS is searchUpFS for the operating system file system, starting at
+// the working directory. It stops at the file system root.
+func searchUpOS(names []string) (string, bool, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", false, fmt.Errorf("get working directory: %w", err)
+	}
+	for {
+		for _, name := range append(names[:len(names):len(names)], ".git") {
+			_, err := os.Stat(filepath.Join(dir, name))
+			if err == nil {
+				return dir, name != ".git", nil
+			}
+			if !errors.Is(err, fs.ErrNotExist) {
+				return "", false, fmt.Errorf("stat %s: %w", filepath.Join(dir, name), err)
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false, nil
+		}
+		dir = parent
+	}
+}
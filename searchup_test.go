@@ -0,0 +1,42 @@
+package dotenv
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func Test_searchUp(t *testing.T) {
+	fs := fstest.MapFS{
+		".env":                     &fstest.MapFile{Data: []byte("LEVEL=outside\n")},
+		"repo/.git/HEAD":           &fstest.MapFile{Data: []byte("ref: refs/heads/main\n")},
+		"repo/.env":                &fstest.MapFile{Data: []byte("LEVEL=repo\n")},
+		"repo/svc/cmd/main.go":     &fstest.MapFile{Data: []byte("package main\n")},
+		"other/.git/HEAD":          &fstest.MapFile{Data: []byte("ref: refs/heads/main\n")},
+		"other/pkg/deep/readme.md": &fstest.MapFile{Data: []byte("no env\n")},
+		"plain/dir/readme.md":      &fstest.MapFile{Data: []byte("no env\n")},
+	}
+
+	t.Run("finds nearest dotenv in parents", func(t *testing.T) {
+		env, err := Parse(WithPaths("repo/svc/cmd"), WithFs(fs), WithSearchUp())
+		assertNoError(t, err)
+		assertEqual(t, env["LEVEL"], "repo")
+	})
+
+	t.Run("stops at git root", func(t *testing.T) {
+		env, err := Parse(WithPaths("other/pkg/deep"), WithFs(fs), WithSearchUp())
+		assertNoError(t, err)
+		assertEqual(t, len(env), 0)
+	})
+
+	t.Run("stops at fs root", func(t *testing.T) {
+		env, err := Parse(WithPaths("plain/dir"), WithFs(fs), WithSearchUp())
+		assertNoError(t, err)
+		assertEqual(t, env["LEVEL"], "outside")
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		env, err := Parse(WithPaths("repo/svc/cmd"), WithFs(fs))
+		assertNoError(t, err)
+		assertEqual(t, len(env), 0)
+	})
+}
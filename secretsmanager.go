@@ -0,0 +1,55 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SecretsManagerClient is the part of the AWS Secrets Manager API used by
+// SecretsManagerProvider: GetSecretValue returning SecretString. An empty
+// versionStage means the default, AWSCURRENT. The package does not depend
+// on the AWS SDK; adapt *secretsmanager.Client from aws-sdk-go-v2 as shown
+// for SSMClient.
+type SecretsManagerClient interface {
+	GetSecretValue(ctx context.Context, secretID, versionStage string) (string, error)
+}
+
+// SecretsManagerProvider reads a secret from AWS Secrets Manager. A payload
+// holding a JSON object is read like a FormatJSON file; anything else is
+// parsed as dotenv text.
+type SecretsManagerProvider struct {
+	Client   SecretsManagerClient
+	SecretID string
+	// VersionStage selects a version such as AWSPREVIOUS; empty means
+	// AWSCURRENT.
+	VersionStage string
+	// Options configure how the payload is parsed.
+	Options []Option
+}
+
+// Fetch reads and parses the secret.
+func (p *SecretsManagerProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	if p.Client == nil {
+		return nil, errors.New("secretsmanager: client is required")
+	}
+	payload, err := p.Client.GetSecretValue(ctx, p.SecretID, p.VersionStage)
+	if err != nil {
+		return nil, fmt.Errorf("secretsmanager %s: %w", p.SecretID, err)
+	}
+	return parsePayload(ctx, payload, p.String(), p.Options)
+}
+
+// String returns a secretsmanager:// URL naming the secret.
+func (p *SecretsManagerProvider) String() string {
+	return "secretsmanager://" + p.SecretID
+}
+
+// parsePayload parses a secret that is either a JSON object or dotenv text.
+func parsePayload(ctx context.Context, payload, name string, userOptions []Option) (map[string]string, error) {
+	if strings.HasPrefix(strings.TrimSpace(payload), "{") {
+		userOptions = append(userOptions[:len(userOptions):len(userOptions)], WithFormat(FormatJSON))
+	}
+	return parseReader(ctx, strings.NewReader(payload), name, userOptions)
+}
@@ -0,0 +1,32 @@
+package dotenv
+
+import (
+	"context"
+	"testing"
+)
+
+type fakeSecretsManager map[string]string
+
+func (f fakeSecretsManager) GetSecretValue(_ context.Context, id, stage string) (string, error) {
+	return f[id+"@"+stage], nil
+}
+
+func Test_secretsManagerProvider(t *testing.T) {
+	client := fakeSecretsManager{
+		"app/json@":              `{"DB_PASSWORD": "s3cret", "PORT": 5432}`,
+		"app/dotenv@AWSPREVIOUS": "DB_PASSWORD=old\n# comment\nTOKEN='t'\n",
+	}
+
+	env, err := (&SecretsManagerProvider{Client: client, SecretID: "app/json"}).Fetch(context.Background())
+	assertNoError(t, err)
+	assertEqual(t, env["DB_PASSWORD"], "s3cret")
+	assertEqual(t, env["PORT"], "5432")
+
+	p := &SecretsManagerProvider{Client: client, SecretID: "app/dotenv", VersionStage: "AWSPREVIOUS"}
+	env, err = p.Fetch(context.Background())
+	assertNoError(t, err)
+	assertEqual(t, len(env), 2)
+	assertEqual(t, env["DB_PASSWORD"], "old")
+	assertEqual(t, env["TOKEN"], "t")
+	assertEqual(t, p.String(), "secretsmanager://app/dotenv")
+}
@@ -0,0 +1,91 @@
+package dotenv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SecretsServiceProvider fetches secrets from a hosted secrets manager over
+// a small, vendor-neutral HTTP contract, so services in the style of Doppler
+// or Infisical can be used directly or through a thin proxy:
+//
+//	GET {BaseURL}/v1/secrets?project={Project}&environment={Environment}
+//	Authorization: Bearer {Token}
+//
+// answered with 200 and
+//
+//	{"secrets": {"KEY": "value", ...}}
+//
+// Any other status is an error; a JSON body of the form {"error": "..."} is
+// included in the message.
+type SecretsServiceProvider struct {
+	BaseURL     string
+	Token       string
+	Project     string
+	Environment string
+	// Client sends the requests, http.DefaultClient if nil.
+	Client *http.Client
+	// Timeout bounds each request when positive.
+	Timeout time.Duration
+}
+
+// Fetch requests the secrets of the configured project and environment.
+func (p *SecretsServiceProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+	q := url.Values{"project": {p.Project}, "environment": {p.Environment}}
+	u := strings.TrimSuffix(p.BaseURL, "/") + "/v1/secrets?" + q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.Token)
+	req.Header.Set("Accept", "application/json")
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Secrets map[string]string `json:"secrets"`
+		Error   string            `json:"error"`
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("secrets service: read response: %w", err)
+	}
+	decodeErr := json.Unmarshal(data, &body)
+	if resp.StatusCode != http.StatusOK {
+		if body.Error != "" {
+			return nil, fmt.Errorf("secrets service: unexpected status %s: %s", resp.Status, body.Error)
+		}
+		return nil, fmt.Errorf("secrets service: unexpected status %s", resp.Status)
+	}
+	if decodeErr != nil {
+		return nil, fmt.Errorf("secrets service: decode response: %w", decodeErr)
+	}
+	if body.Secrets == nil {
+		return nil, fmt.Errorf("secrets service: response has no secrets object")
+	}
+	return body.Secrets, nil
+}
+
+// String names the project and environment.
+func (p *SecretsServiceProvider) String() string {
+	return strings.TrimSuffix(p.BaseURL, "/") + "/" + p.Project + "/" + p.Environment
+}
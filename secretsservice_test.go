@@ -0,0 +1,36 @@
+package dotenv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_secretsServiceProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": "invalid token"}`))
+			return
+		}
+		if r.URL.Path != "/v1/secrets" || r.URL.Query().Get("project") != "api" || r.URL.Query().Get("environment") != "prd" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"secrets": {"DB_PASSWORD": "s3cret"}}`))
+	}))
+	defer srv.Close()
+
+	p := &SecretsServiceProvider{BaseURL: srv.URL + "/", Token: "tok", Project: "api", Environment: "prd"}
+	env, err := p.Fetch(context.Background())
+	assertNoError(t, err)
+	assertEqual(t, env["DB_PASSWORD"], "s3cret")
+
+	p.Token = "bad"
+	_, err = p.Fetch(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "invalid token") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package dotenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+// ReloadOnSignal loads the configured files and loads them again every time
+// sig arrives, until ctx is done, for daemons that reload their
+// configuration on SIGHUP:
+//
+//	go dotenv.ReloadOnSignal(ctx, syscall.SIGHUP, dotenv.WithPaths(".env"))
+//
+// Every reload first undoes the changes of the previous one, so keys removed
+// from the files disappear and WithNoOverride keeps protecting only the
+// variables that were set before the first load. A reload that fails to
+// parse changes nothing and is logged as a warning. ReloadOnSignal returns
+// the error of the first load, or nil once ctx is done. Like
+// LoadWithRestore, it is meant for use without WithSetter.
+func ReloadOnSignal(ctx context.Context, sig os.Signal, userOptions ...Option) error {
+	opts, err := buildOptions(userOptions)
+	if err != nil {
+		return fmt.Errorf("can't reload .env file with these options: %w", err)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, sig)
+	defer signal.Stop(signals)
+
+	priors, err := reload(ctx, opts, nil)
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-signals:
+			opts.Logger.Info("signal received; reloading", "signal", sig.String())
+			if priors, err = reload(ctx, opts, priors); err != nil {
+				opts.Logger.Warn("reload failed", "error", err)
+			}
+		}
+	}
+}
+
+// reload parses the files and, if that succeeds, reverts priors and applies
+// the new values. It returns the values to revert on the next reload.
+func reload(ctx context.Context, opts Options, priors []priorValue) ([]priorValue, error) {
+	env, err := parse(ctx, opts)
+	if err != nil {
+		return priors, err
+	}
+	applyMu.Lock()
+	defer applyMu.Unlock()
+	if err := restorePriors(priors); err != nil {
+		return nil, err
+	}
+	return apply(opts, env, &Report{})
+}
@@ -0,0 +1,54 @@
+package dotenv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func Test_reloadOnSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals can't be sent to the own process on windows")
+	}
+	dir := t.TempDir()
+	write := func(data string) {
+		t.Helper()
+		assertNoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte(data), 0o600))
+	}
+	write("SIG_A=1\nSIG_B=1\n")
+	t.Setenv("SIG_A", "")
+	os.Unsetenv("SIG_A")
+	t.Setenv("SIG_B", "")
+	os.Unsetenv("SIG_B")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- ReloadOnSignal(ctx, syscall.SIGHUP, WithFs(os.DirFS(dir))) }()
+
+	waitFor := func(key, want string, set bool) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if v, ok := os.LookupEnv(key); ok == set && v == want {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+		t.Fatalf("%s did not become %q (set=%v)", key, want, set)
+	}
+	waitFor("SIG_A", "1", true)
+
+	write("SIG_A=2\n")
+	self, err := os.FindProcess(os.Getpid())
+	assertNoError(t, err)
+	assertNoError(t, self.Signal(syscall.SIGHUP))
+	waitFor("SIG_A", "2", true)
+	waitFor("SIG_B", "", false)
+
+	cancel()
+	assertNoError(t, <-done)
+}
@@ -0,0 +1,37 @@
+package dotenv
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_withSlog(t *testing.T) {
+	fs := fstest.MapFS{
+		".env":       &fstest.MapFile{Data: []byte("A=1\n")},
+		".env.local": &fstest.MapFile{Data: []byte("A=2\n")},
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	_, err := Parse(WithFs(fs), WithPaths(".env", ".env.local", "missing"), WithSlog(logger))
+	assertNoError(t, err)
+	logs := buf.String()
+	for _, want := range []string{
+		`level=DEBUG msg="source read" source=.env keys=1`,
+		`level=DEBUG msg="key overridden" key=A file=.env.local line=1 previous_file=.env previous_line=1`,
+		`level=WARN msg="path not found" path=missing`,
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("missing %q in:\n%s", want, logs)
+		}
+	}
+
+	buf.Reset()
+	logger = slog.New(slog.NewTextHandler(&buf, nil))
+	_, err = Parse(WithFs(fs), WithPaths(".env", ".env.local"), WithSlog(logger))
+	assertNoError(t, err)
+	assertEqual(t, buf.String(), "")
+}
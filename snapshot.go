@@ -0,0 +1,121 @@
+package dotenv
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Snapshot is an immutable view of parsed values with typed getters, for
+// libraries that consume configuration without going through the process
+// environment. It is safe for concurrent use.
+type Snapshot struct {
+	values  map[string]string
+	sources map[string]Source
+}
+
+// ParseSnapshot parses the configured paths like Parse and returns the
+// values as a Snapshot.
+func ParseSnapshot(userOptions ...Option) (*Snapshot, error) {
+	opts, err := buildOptions(userOptions)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse .env file with these options: %w", err)
+	}
+	env, err := parse(context.Background(), opts)
+	if err != nil {
+		return nil, err
+	}
+	return snapshotOf(env), nil
+}
+
+// NewSnapshot returns a Snapshot of a copy of values.
+func NewSnapshot(values map[string]string) *Snapshot {
+	return &Snapshot{values: maps.Clone(values), sources: map[string]Source{}}
+}
+
+func snapshotOf(env entries) *Snapshot {
+	s := &Snapshot{values: env.values(), sources: make(map[string]Source, len(env))}
+	for key, e := range env {
+		if !e.unset {
+			s.sources[key] = Source{File: e.file, Line: e.line}
+		}
+	}
+	return s
+}
+
+// Lookup returns the value of key and whether it is set.
+func (s *Snapshot) Lookup(key string) (string, bool) {
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// Keys returns the keys of the snapshot, sorted.
+func (s *Snapshot) Keys() []string {
+	return slices.Sorted(maps.Keys(s.values))
+}
+
+// Map returns a copy of the values.
+func (s *Snapshot) Map() map[string]string {
+	return maps.Clone(s.values)
+}
+
+// Source returns where the value of key was defined. Snapshots made with
+// NewSnapshot have no sources.
+func (s *Snapshot) Source(key string) (Source, bool) {
+	src, ok := s.sources[key]
+	return src, ok
+}
+
+// GetString returns the value of key, or def if it is not set.
+func (s *Snapshot) GetString(key, def string) string {
+	if v, ok := s.values[key]; ok {
+		return v
+	}
+	return def
+}
+
+// GetInt returns the value of key as an int, or def if it is not set or
+// not an integer.
+func (s *Snapshot) GetInt(key string, def int) int {
+	return get(s, key, def, strconv.Atoi)
+}
+
+// GetBool returns the value of key as parsed by strconv.ParseBool, or def if
+// it is not set or not a boolean.
+func (s *Snapshot) GetBool(key string, def bool) bool {
+	return get(s, key, def, strconv.ParseBool)
+}
+
+// GetDuration returns the value of key as parsed by time.ParseDuration, or
+// def if it is not set or not a duration.
+func (s *Snapshot) GetDuration(key string, def time.Duration) time.Duration {
+	return get(s, key, def, time.ParseDuration)
+}
+
+// GetURL returns the value of key as an absolute URL, or def if it is not
+// set or not an absolute URL.
+func (s *Snapshot) GetURL(key string, def *url.URL) *url.URL {
+	return get(s, key, def, func(v string) (*url.URL, error) {
+		u, err := url.Parse(v)
+		if err == nil && !u.IsAbs() {
+			err = fmt.Errorf("%q is not an absolute URL", v)
+		}
+		return u, err
+	})
+}
+
+func get[T any](s *Snapshot, key string, def T, parse func(string) (T, error)) T {
+	v, ok := s.values[key]
+	if !ok {
+		return def
+	}
+	parsed, err := parse(v)
+	if err != nil {
+		return def
+	}
+	return parsed
+}
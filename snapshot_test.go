@@ -0,0 +1,50 @@
+package dotenv
+
+import (
+	"net/url"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func Test_snapshot(t *testing.T) {
+	fs := fstest.MapFS{".env": &fstest.MapFile{Data: []byte(`NAME=api
+PORT=8080
+DEBUG=true
+TIMEOUT=1m30s
+ENDPOINT=https://example.com/v1
+BAD_PORT=eighty
+RELATIVE=/v1
+`)}}
+
+	s, err := ParseSnapshot(WithFs(fs))
+	assertNoError(t, err)
+
+	assertEqual(t, s.GetString("NAME", "x"), "api")
+	assertEqual(t, s.GetString("MISSING", "x"), "x")
+	assertEqual(t, s.GetInt("PORT", 1), 8080)
+	assertEqual(t, s.GetInt("BAD_PORT", 1), 1)
+	assertEqual(t, s.GetBool("DEBUG", false), true)
+	assertEqual(t, s.GetBool("MISSING", true), true)
+	assertEqual(t, s.GetDuration("TIMEOUT", 0), 90*time.Second)
+	assertEqual(t, s.GetURL("ENDPOINT", nil).Host, "example.com")
+	def := &url.URL{Scheme: "http", Host: "localhost"}
+	assertEqual(t, s.GetURL("RELATIVE", def), def)
+
+	assertEqual(t, len(s.Keys()), 7)
+	assertEqual(t, s.Keys()[0], "BAD_PORT")
+	src, ok := s.Source("PORT")
+	assertEqual(t, ok, true)
+	assertEqual(t, src, Source{File: ".env", Line: 2})
+
+	m := s.Map()
+	m["NAME"] = "changed"
+	assertEqual(t, s.GetString("NAME", ""), "api")
+
+	values := map[string]string{"A": "1"}
+	s = NewSnapshot(values)
+	values["A"] = "2"
+	v, ok := s.Lookup("A")
+	assertEqual(t, v, "1")
+	assertEqual(t, ok, true)
+}
@@ -0,0 +1,22 @@
+package dotenv
+
+// Source locates the line a loaded value was defined on.
+type Source struct {
+	File string
+	Line int
+}
+
+// Sources returns where each variable exported by Load came from. Later
+// loads update the entries for the keys they set. The returned map is a
+// copy and safe to modify.
+func Sources() map[string]Source {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	srcs := make(map[string]Source, len(registry))
+	for key, o := range registry {
+		if !o.unset {
+			srcs[key] = o.source
+		}
+	}
+	return srcs
+}
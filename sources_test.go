@@ -0,0 +1,27 @@
+package dotenv
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_sources(t *testing.T) {
+	fs := fstest.MapFS{
+		"a/.env": &fstest.MapFile{Data: []byte(`SRC_A=1
+SRC_B=1
+`)},
+		"b/.env": &fstest.MapFile{Data: []byte(`# override
+SRC_B=2
+`)},
+	}
+	os.Unsetenv("SRC_A")
+	os.Unsetenv("SRC_B")
+
+	err := Load(WithPaths("a", "b"), WithFs(fs))
+	assertNoError(t, err)
+
+	srcs := Sources()
+	assertEqual(t, srcs["SRC_A"], Source{File: "a/.env", Line: 1})
+	assertEqual(t, srcs["SRC_B"], Source{File: "b/.env", Line: 2})
+}
@@ -0,0 +1,85 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SSMParameter is a parameter returned by an SSMClient.
+type SSMParameter struct {
+	Name  string
+	Value string
+}
+
+// SSMClient is the part of the AWS Systems Manager API used by SSMProvider:
+// one page of GetParametersByPath. The package does not depend on the AWS
+// SDK; with aws-sdk-go-v2 an adapter looks like this:
+//
+//	type ssmAdapter struct{ c *ssm.Client }
+//
+//	func (a ssmAdapter) GetParametersByPath(ctx context.Context, path string, recursive, decrypt bool, next string) ([]dotenv.SSMParameter, string, error) {
+//		in := &ssm.GetParametersByPathInput{Path: &path, Recursive: &recursive, WithDecryption: &decrypt}
+//		if next != "" {
+//			in.NextToken = &next
+//		}
+//		out, err := a.c.GetParametersByPath(ctx, in)
+//		if err != nil {
+//			return nil, "", err
+//		}
+//		params := make([]dotenv.SSMParameter, len(out.Parameters))
+//		for i, p := range out.Parameters {
+//			params[i] = dotenv.SSMParameter{Name: *p.Name, Value: *p.Value}
+//		}
+//		return params, aws.ToString(out.NextToken), nil
+//	}
+type SSMClient interface {
+	GetParametersByPath(ctx context.Context, path string, recursive, decrypt bool, nextToken string) (params []SSMParameter, next string, err error)
+}
+
+// SSMProvider reads all parameters under Path from AWS SSM Parameter Store.
+// Parameter names are mapped to keys by dropping Path and converting the
+// rest with NormalizeUpperSnake, so /myapp/prod/db/host under /myapp/prod
+// becomes DB_HOST.
+type SSMProvider struct {
+	Client SSMClient
+	Path   string
+	// Recursive includes parameters in nested paths.
+	Recursive bool
+	// Decrypt decrypts SecureString parameters.
+	Decrypt bool
+}
+
+// Fetch reads every page of parameters under Path.
+func (p *SSMProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	if p.Client == nil {
+		return nil, errors.New("ssm: client is required")
+	}
+	prefix := strings.TrimSuffix(p.Path, "/") + "/"
+	values := make(map[string]string)
+	next := ""
+	for {
+		params, token, err := p.Client.GetParametersByPath(ctx, p.Path, p.Recursive, p.Decrypt, next)
+		if err != nil {
+			return nil, fmt.Errorf("ssm %s: %w", p.Path, err)
+		}
+		for _, param := range params {
+			name := strings.TrimPrefix(param.Name, prefix)
+			key := NormalizeUpperSnake(strings.Trim(name, "/"))
+			if key == "" {
+				continue
+			}
+			values[key] = param.Value
+		}
+		if token == "" {
+			return values, nil
+		}
+		next = token
+	}
+}
+
+// String returns an ssm:// URL naming the path.
+func (p *SSMProvider) String() string {
+	return "ssm://" + p.Path
+}
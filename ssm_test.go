@@ -0,0 +1,40 @@
+package dotenv
+
+import (
+	"context"
+	"testing"
+)
+
+type fakeSSM struct {
+	pages   [][]SSMParameter
+	decrypt bool
+}
+
+func (f *fakeSSM) GetParametersByPath(_ context.Context, path string, recursive, decrypt bool, next string) ([]SSMParameter, string, error) {
+	f.decrypt = decrypt
+	i := 0
+	if next != "" {
+		i = int(next[0] - '0')
+	}
+	token := ""
+	if i+1 < len(f.pages) {
+		token = string(rune('0' + i + 1))
+	}
+	return f.pages[i], token, nil
+}
+
+func Test_ssmProvider(t *testing.T) {
+	client := &fakeSSM{pages: [][]SSMParameter{
+		{{Name: "/myapp/prod/db/host", Value: "db.local"}},
+		{{Name: "/myapp/prod/api-key", Value: "secret"}},
+	}}
+	p := &SSMProvider{Client: client, Path: "/myapp/prod", Recursive: true, Decrypt: true}
+
+	env, err := p.Fetch(context.Background())
+	assertNoError(t, err)
+	assertEqual(t, len(env), 2)
+	assertEqual(t, env["DB_HOST"], "db.local")
+	assertEqual(t, env["API_KEY"], "secret")
+	assertEqual(t, client.decrypt, true)
+	assertEqual(t, p.String(), "ssm:///myapp/prod")
+}
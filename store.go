@@ -0,0 +1,174 @@
+package dotenv
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultReloadInterval is how often a Store reloads without
+// WithReloadInterval.
+const DefaultReloadInterval = 5 * time.Second
+
+// WithReloadInterval sets how often a Store reloads its sources. Values are
+// compared after every reload and subscribers are only notified of changes.
+func WithReloadInterval(d time.Duration) Option {
+	return func(o *Options) {
+		o.ReloadInterval = d
+	}
+}
+
+// Change is sent to subscribers of a Store when a reload changed the
+// values.
+type Change struct {
+	Old, New *Snapshot
+}
+
+// Store keeps the current Snapshot of its sources and reloads them in the
+// background, swapping snapshots atomically when the values change. It
+// never touches the process environment.
+//
+//	store, err := dotenv.NewStore(ctx, dotenv.WithPaths(".env"))
+//	...
+//	changes, cancel := store.Subscribe()
+//	defer cancel()
+//	for c := range changes {
+//		log.Printf("config changed, LOG_LEVEL=%s", c.New.GetString("LOG_LEVEL", "info"))
+//	}
+type Store struct {
+	opts    Options
+	current atomic.Pointer[Snapshot]
+
+	reloadMu sync.Mutex // serializes reloads
+
+	mu     sync.Mutex
+	subs   map[chan Change]struct{}
+	closed bool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewStore parses the configured sources and starts reloading them every
+// reload interval until ctx is done or Close is called. It fails if the
+// first parse fails; later failures keep the current snapshot and are
+// logged as warnings.
+func NewStore(ctx context.Context, userOptions ...Option) (*Store, error) {
+	opts, err := buildOptions(userOptions)
+	if err != nil {
+		return nil, fmt.Errorf("can't create store with these options: %w", err)
+	}
+	env, err := parse(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{opts: opts, subs: map[chan Change]struct{}{}, done: make(chan struct{})}
+	s.current.Store(snapshotOf(env))
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	go s.run(ctx)
+	return s, nil
+}
+
+// Current returns the latest snapshot without locking.
+func (s *Store) Current() *Snapshot {
+	return s.current.Load()
+}
+
+// Subscribe returns a channel receiving a Change after every reload that
+// changed the values, and a function to unsubscribe. A subscriber that falls
+// behind only gets the latest change. The channel is closed when the store
+// is closed or unsubscribed.
+func (s *Store) Subscribe() (<-chan Change, func()) {
+	ch := make(chan Change, 1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	s.subs[ch] = struct{}{}
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.subs[ch]; ok {
+			delete(s.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Reload parses the sources now, swapping in and announcing a new snapshot
+// if the values changed. It reports whether they did.
+func (s *Store) Reload(ctx context.Context) (bool, error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	env, err := parse(ctx, s.opts)
+	if err != nil {
+		return false, err
+	}
+	next := snapshotOf(env)
+	prev := s.current.Load()
+	if maps.Equal(prev.values, next.values) {
+		return false, nil
+	}
+	s.current.Store(next)
+	s.notify(Change{Old: prev, New: next})
+	return true, nil
+}
+
+// Close stops reloading and closes all subscriptions.
+func (s *Store) Close() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *Store) run(ctx context.Context) {
+	defer close(s.done)
+	defer s.closeSubs()
+
+	interval := s.opts.ReloadInterval
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Reload(ctx); err != nil && ctx.Err() == nil {
+				s.opts.Logger.Warn("reload failed; keeping current values", "error", err)
+			}
+		}
+	}
+}
+
+func (s *Store) notify(c Change) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subs {
+		// Replace a change the subscriber has not picked up yet.
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- c
+	}
+}
+
+func (s *Store) closeSubs() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for ch := range s.subs {
+		close(ch)
+	}
+	clear(s.subs)
+}
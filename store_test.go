@@ -0,0 +1,61 @@
+package dotenv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_store(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, ".env")
+	write := func(data string) {
+		t.Helper()
+		assertNoError(t, os.WriteFile(name, []byte(data), 0o600))
+	}
+	write("LEVEL=info\n")
+
+	store, err := NewStore(context.Background(), WithFs(os.DirFS(dir)), WithReloadInterval(5*time.Millisecond))
+	assertNoError(t, err)
+	defer store.Close()
+	assertEqual(t, store.Current().GetString("LEVEL", ""), "info")
+
+	changes, cancel := store.Subscribe()
+	defer cancel()
+
+	write("LEVEL=debug\n")
+	select {
+	case c := <-changes:
+		assertEqual(t, c.Old.GetString("LEVEL", ""), "info")
+		assertEqual(t, c.New.GetString("LEVEL", ""), "debug")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change received")
+	}
+	assertEqual(t, store.Current().GetString("LEVEL", ""), "debug")
+
+	// A broken file keeps the current values.
+	write("LEVEL=\"open\n")
+	changed, err := store.Reload(context.Background())
+	if err == nil || changed {
+		t.Fatalf("expected failed reload, got changed=%v err=%v", changed, err)
+	}
+	assertEqual(t, store.Current().GetString("LEVEL", ""), "debug")
+
+	store.Close()
+	if _, ok := <-changes; ok {
+		t.Fatal("subscription should be closed")
+	}
+	closed, _ := store.Subscribe()
+	if _, ok := <-closed; ok {
+		t.Fatal("subscribing to a closed store should return a closed channel")
+	}
+}
+
+func Test_storeInitialError(t *testing.T) {
+	_, err := NewStore(context.Background(), WithFs(os.DirFS(t.TempDir())), WithPaths("missing"), WithRequiredPaths())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,43 @@
+package dotenv
+
+import (
+	"slices"
+)
+
+// WithSync makes Load revert variables that an earlier Load set but that
+// the files no longer define, so that a long-running process that reloads
+// its configuration stays consistent with the files. Only variables listed
+// by OwnedKeys and not changed by anyone else since are touched; they get
+// back the value they had before they were first loaded, or are unset.
+// Reverted keys are listed in Report.Removed. WithSync has no effect with
+// WithSetter.
+func WithSync() Option {
+	return func(o *Options) {
+		o.Sync = true
+	}
+}
+
+// syncRemoved reverts the variables owned by earlier loads that env no
+// longer defines and returns the states it replaced.
+func syncRemoved(opts Options, env entries, report *Report) ([]priorValue, error) {
+	registryMu.Lock()
+	var stale []string
+	for key, o := range registry {
+		if _, ok := env[key]; !ok && o.current() {
+			stale = append(stale, key)
+		}
+	}
+	slices.Sort(stale)
+	for _, key := range stale {
+		o := registry[key]
+		kr := KeyReport{Key: key, File: o.source.File, Line: o.source.Line}
+		report.Removed = append(report.Removed, kr)
+		opts.logKey("variable removed", kr, "")
+	}
+	registryMu.Unlock()
+
+	if opts.DryRun {
+		return nil, nil
+	}
+	return release(stale)
+}
@@ -0,0 +1,50 @@
+package dotenv
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_sync(t *testing.T) {
+	t.Setenv("SYNC_A", "")
+	t.Setenv("SYNC_B", "")
+	os.Unsetenv("SYNC_A")
+	os.Unsetenv("SYNC_B")
+	t.Setenv("SYNC_OTHER", "untouched")
+	fs := fstest.MapFS{
+		"before.env": &fstest.MapFile{Data: []byte("SYNC_A=1\nSYNC_B=1\n")},
+		"after.env":  &fstest.MapFile{Data: []byte("SYNC_A=2\n")},
+	}
+	assertNoError(t, Load(WithFs(fs), WithPaths("before.env")))
+	assertEqual(t, os.Getenv("SYNC_B"), "1")
+
+	removedKeys := func(r *Report) []string {
+		var keys []string
+		for _, kr := range r.Removed {
+			keys = append(keys, kr.Key)
+		}
+		return keys
+	}
+
+	report, err := LoadReport(WithFs(fs), WithPaths("after.env"), WithSync(), WithDryRun())
+	assertNoError(t, err)
+	if !slices.Contains(removedKeys(report), "SYNC_B") {
+		t.Fatalf("SYNC_B not reported as removed: %v", report.Removed)
+	}
+	assertEqual(t, os.Getenv("SYNC_B"), "1")
+
+	report, err = LoadReport(WithFs(fs), WithPaths("after.env"), WithSync())
+	assertNoError(t, err)
+	keys := removedKeys(report)
+	if !slices.Contains(keys, "SYNC_B") || slices.Contains(keys, "SYNC_A") || slices.Contains(keys, "SYNC_OTHER") {
+		t.Fatalf("unexpected removed keys %v", keys)
+	}
+	assertEqual(t, os.Getenv("SYNC_A"), "2")
+	_, ok := os.LookupEnv("SYNC_B")
+	assertEqual(t, ok, false)
+	assertEqual(t, os.Getenv("SYNC_OTHER"), "untouched")
+	_, ok = Sources()["SYNC_B"]
+	assertEqual(t, ok, false)
+}
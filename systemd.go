@@ -0,0 +1,249 @@
+package dotenv
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// systemd parser states, following systemd's env-file parser.
+const (
+	sdPreKey = iota
+	sdKey
+	sdPreValue
+	sdValue
+	sdValueEscape
+	sdSingleQuote
+	sdDoubleQuote
+	sdDoubleQuoteEscape
+	sdComment
+	sdCommentEscape
+)
+
+// scanSystemd splits r into statements using the rules of systemd's
+// EnvironmentFile=: '#' and ';' start comment lines, a trailing backslash
+// continues a line, quotes may only open at the start of a value (or right
+// after a closing quote, which concatenates) and whitespace around keys and
+// unquoted values is dropped. Invalid keys are skipped, or reported in
+// strict mode.
+func scanSystemd(opts Options, r io.Reader, envPath string, fn func(statement) error) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", envPath, err)
+	}
+	content := string(data)
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	var (
+		state     = sdPreKey
+		start     = 0 // offset of the current statement
+		lineNo    = 1
+		st        = statement{line: 1}
+		key       strings.Builder
+		value     strings.Builder
+		keyCol    int
+		keepValue int  // value length up to the last significant byte
+		assigned  bool // the '=' of the current statement was seen
+		col       = 0
+		problems  []error
+	)
+	reset := func(next int) {
+		state = sdPreKey
+		start = next
+		st = statement{line: lineNo + 1}
+		key.Reset()
+		value.Reset()
+		keepValue = 0
+		assigned = false
+	}
+	emit := func(end int) error {
+		st.raw = content[start:end]
+		if assigned {
+			k := strings.TrimRight(key.String(), " \t")
+			if i := systemdInvalidKeyChar(k); i >= 0 {
+				if opts.Strict {
+					problems = append(problems, &ParseError{
+						File:   envPath,
+						Line:   st.line,
+						Col:    keyCol + i,
+						Reason: fmt.Sprintf("invalid character %q in key", k[i]),
+					})
+				}
+				st.key = ""
+			} else {
+				st.key = k
+				st.value = value.String()[:keepValue]
+			}
+		}
+		return fn(st)
+	}
+
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		col++
+		if opts.MaxLineLength > 0 && col > opts.MaxLineLength && c != '\n' {
+			return joinErrors(append(problems, &ParseError{
+				File:   envPath,
+				Line:   lineNo,
+				Col:    col,
+				Reason: fmt.Sprintf("line exceeds maximum length of %d bytes", opts.MaxLineLength),
+			}))
+		}
+		switch state {
+		case sdPreKey:
+			switch {
+			case c == '#' || c == ';':
+				state = sdComment
+			case c == '=':
+				if opts.Strict {
+					problems = append(problems, &ParseError{File: envPath, Line: st.line, Col: col, Reason: "empty key"})
+				}
+				state = sdComment
+			case c == '\n':
+				if err := emit(i); err != nil {
+					return err
+				}
+				reset(i + 1)
+			case c == ' ' || c == '\t' || c == '\r':
+			default:
+				state = sdKey
+				keyCol = col
+				key.WriteByte(c)
+			}
+		case sdKey:
+			switch c {
+			case '\n':
+				if opts.Strict {
+					problems = append(problems, &ParseError{File: envPath, Line: st.line, Col: keyCol, Reason: "missing '=' after key"})
+				}
+				if err := emit(i); err != nil {
+					return err
+				}
+				reset(i + 1)
+			case '=':
+				state = sdPreValue
+				assigned = true
+			default:
+				key.WriteByte(c)
+			}
+		case sdPreValue:
+			switch c {
+			case '\n':
+				if err := emit(i); err != nil {
+					return err
+				}
+				reset(i + 1)
+			case '\'':
+				state = sdSingleQuote
+				st.setValueStart(col, c)
+			case '"':
+				state = sdDoubleQuote
+				st.setValueStart(col, c)
+			case '\\':
+				state = sdValueEscape
+				st.setValueStart(col, 0)
+			case ' ', '\t', '\r':
+			default:
+				state = sdValue
+				st.setValueStart(col, 0)
+				value.WriteByte(c)
+				keepValue = value.Len()
+			}
+		case sdValue:
+			switch c {
+			case '\n':
+				if err := emit(i); err != nil {
+					return err
+				}
+				reset(i + 1)
+			case '\\':
+				state = sdValueEscape
+			default:
+				value.WriteByte(c)
+				if c != ' ' && c != '\t' && c != '\r' {
+					keepValue = value.Len()
+				}
+			}
+		case sdValueEscape:
+			state = sdValue
+			if c != '\n' {
+				value.WriteByte(c)
+				keepValue = value.Len()
+			}
+		case sdSingleQuote:
+			if c == '\'' {
+				state = sdPreValue
+			} else {
+				value.WriteByte(c)
+				keepValue = value.Len()
+			}
+		case sdDoubleQuote:
+			switch c {
+			case '"':
+				state = sdPreValue
+			case '\\':
+				state = sdDoubleQuoteEscape
+			default:
+				value.WriteByte(c)
+				keepValue = value.Len()
+			}
+		case sdDoubleQuoteEscape:
+			state = sdDoubleQuote
+			switch c {
+			case '"', '\\', '`', '$':
+				value.WriteByte(c)
+			case '\n':
+			default:
+				value.WriteByte('\\')
+				value.WriteByte(c)
+			}
+			keepValue = value.Len()
+		case sdComment:
+			switch c {
+			case '\\':
+				state = sdCommentEscape
+			case '\n':
+				if err := emit(i); err != nil {
+					return err
+				}
+				reset(i + 1)
+			}
+		case sdCommentEscape:
+			state = sdComment
+		}
+
+		if c == '\n' {
+			lineNo++
+			col = 0
+		}
+	}
+
+	if start < len(content) {
+		if err := emit(len(content) - 1); err != nil {
+			return err
+		}
+	}
+	return joinErrors(problems)
+}
+
+// setValueStart records where a value begins the first time it is called.
+func (st *statement) setValueStart(col int, quote byte) {
+	if st.valueCol == 0 {
+		st.valueCol = col
+		st.quote = quote
+	}
+}
+
+// systemdInvalidKeyChar returns the index of the first byte not allowed in a
+// systemd environment variable name, or -1.
+func systemdInvalidKeyChar(key string) int {
+	if key == "" {
+		return 0
+	}
+	if i := nameLen(key); i < len(key) {
+		return i
+	}
+	return -1
+}
@@ -0,0 +1,371 @@
+package dotenv
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// scanTOML reads a TOML document and calls fn with a statement per leaf, see
+// FormatTOML.
+func scanTOML(_ Options, r io.Reader, envPath string, fn func(statement) error) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", envPath, err)
+	}
+	p := &tomlParser{file: envPath, data: data, s: string(data), emit: fn}
+	return p.parse()
+}
+
+type tomlParser struct {
+	file  string
+	data  []byte
+	s     string
+	pos   int
+	emit  func(statement) error
+	table string
+}
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	line, col := position(p.data, int64(p.pos))
+	return &ParseError{File: p.file, Line: line, Col: col, Reason: fmt.Sprintf(format, args...)}
+}
+
+func (p *tomlParser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+// skipSpace skips blanks and, with newlines set, comments and line breaks.
+func (p *tomlParser) skipSpace(newlines bool) {
+	for p.pos < len(p.s) {
+		switch c := p.s[p.pos]; {
+		case c == ' ' || c == '\t':
+			p.pos++
+		case newlines && (c == '\n' || c == '\r'):
+			p.pos++
+		case newlines && c == '#':
+			p.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) skipComment() {
+	if i := strings.IndexByte(p.s[p.pos:], '\n'); i >= 0 {
+		p.pos += i
+	} else {
+		p.pos = len(p.s)
+	}
+}
+
+// endOfLine consumes trailing blanks and a comment up to the line break.
+func (p *tomlParser) endOfLine() error {
+	p.skipSpace(false)
+	if p.peek() == '#' {
+		p.skipComment()
+	}
+	switch {
+	case p.pos == len(p.s):
+	case strings.HasPrefix(p.s[p.pos:], "\r\n"):
+		p.pos += 2
+	case p.peek() == '\n':
+		p.pos++
+	default:
+		return p.errorf("unexpected %q after value", p.peek())
+	}
+	return nil
+}
+
+func (p *tomlParser) parse() error {
+	for {
+		p.skipSpace(true)
+		if p.pos == len(p.s) {
+			return nil
+		}
+		if p.peek() == '[' {
+			if err := p.header(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		line, _ := position(p.data, int64(p.pos))
+		parts, err := p.key()
+		if err != nil {
+			return err
+		}
+		p.skipSpace(false)
+		if p.peek() != '=' {
+			return p.errorf("expected '=' after key")
+		}
+		p.pos++
+		p.skipSpace(false)
+		if err := p.value(p.name(parts), line); err != nil {
+			return err
+		}
+		if err := p.endOfLine(); err != nil {
+			return err
+		}
+	}
+}
+
+// name returns the variable name of a key in the current table. Top-level
+// keys keep their spelling; everything else is joined in upper snake case.
+func (p *tomlParser) name(parts []string) string {
+	if p.table == "" && len(parts) == 1 {
+		return parts[0]
+	}
+	names := make([]string, 0, len(parts)+1)
+	if p.table != "" {
+		names = append(names, p.table)
+	}
+	for _, part := range parts {
+		names = append(names, NormalizeUpperSnake(part))
+	}
+	return strings.Join(names, "_")
+}
+
+func (p *tomlParser) header() error {
+	if strings.HasPrefix(p.s[p.pos:], "[[") {
+		return p.errorf("arrays of tables are not supported")
+	}
+	p.pos++
+	p.skipSpace(false)
+	parts, err := p.key()
+	if err != nil {
+		return err
+	}
+	p.skipSpace(false)
+	if p.peek() != ']' {
+		return p.errorf("expected ']' after table name")
+	}
+	p.pos++
+	names := make([]string, len(parts))
+	for i, part := range parts {
+		names[i] = NormalizeUpperSnake(part)
+	}
+	p.table = strings.Join(names, "_")
+	return p.endOfLine()
+}
+
+// key reads a possibly dotted key.
+func (p *tomlParser) key() ([]string, error) {
+	var parts []string
+	for {
+		p.skipSpace(false)
+		var part string
+		switch c := p.peek(); {
+		case c == '"' || c == '\'':
+			s, err := p.str()
+			if err != nil {
+				return nil, err
+			}
+			part = s
+		default:
+			start := p.pos
+			for p.pos < len(p.s) && isTOMLBareKeyChar(p.s[p.pos]) {
+				p.pos++
+			}
+			if p.pos == start {
+				return nil, p.errorf("expected a key")
+			}
+			part = p.s[start:p.pos]
+		}
+		parts = append(parts, part)
+		p.skipSpace(false)
+		if p.peek() != '.' {
+			return parts, nil
+		}
+		p.pos++
+	}
+}
+
+func isTOMLBareKeyChar(c byte) bool {
+	return c == '_' || c == '-' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// value reads a value and emits its leaves under name.
+func (p *tomlParser) value(name string, line int) error {
+	set := func(v string) error {
+		return p.emit(statement{line: line, key: name, value: v, quote: '\''})
+	}
+	switch c := p.peek(); c {
+	case '"', '\'':
+		s, err := p.str()
+		if err != nil {
+			return err
+		}
+		return set(s)
+	case '[':
+		p.pos++
+		for i := 0; ; i++ {
+			p.skipSpace(true)
+			if p.peek() == ']' {
+				p.pos++
+				return nil
+			}
+			if err := p.value(name+"_"+strconv.Itoa(i), line); err != nil {
+				return err
+			}
+			p.skipSpace(true)
+			switch p.peek() {
+			case ',':
+				p.pos++
+			case ']':
+			default:
+				return p.errorf("expected ',' or ']' in array")
+			}
+		}
+	case '{':
+		p.pos++
+		for first := true; ; first = false {
+			p.skipSpace(false)
+			if p.peek() == '}' && first {
+				p.pos++
+				return nil
+			}
+			parts, err := p.key()
+			if err != nil {
+				return err
+			}
+			p.skipSpace(false)
+			if p.peek() != '=' {
+				return p.errorf("expected '=' after key")
+			}
+			p.pos++
+			p.skipSpace(false)
+			sub := name
+			for _, part := range parts {
+				sub += "_" + NormalizeUpperSnake(part)
+			}
+			if err := p.value(sub, line); err != nil {
+				return err
+			}
+			p.skipSpace(false)
+			switch p.peek() {
+			case ',':
+				p.pos++
+			case '}':
+				p.pos++
+				return nil
+			default:
+				return p.errorf("expected ',' or '}' in inline table")
+			}
+		}
+	}
+
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n,]}#", p.s[p.pos]) < 0 {
+		p.pos++
+	}
+	v := p.s[start:p.pos]
+	if v == "" {
+		return p.errorf("expected a value")
+	}
+	if c := v[0]; '0' <= c && c <= '9' || c == '+' || c == '-' {
+		v = strings.ReplaceAll(v, "_", "")
+	}
+	return set(v)
+}
+
+// str reads a basic, literal or multi-line string.
+func (p *tomlParser) str() (string, error) {
+	q := p.s[p.pos]
+	delim := string(q)
+	if strings.HasPrefix(p.s[p.pos:], strings.Repeat(delim, 3)) {
+		delim = strings.Repeat(delim, 3)
+	}
+	p.pos += len(delim)
+	multiline := len(delim) == 3
+	if multiline {
+		// A line break right after the opening delimiter is trimmed.
+		if strings.HasPrefix(p.s[p.pos:], "\r\n") {
+			p.pos += 2
+		} else if p.peek() == '\n' {
+			p.pos++
+		}
+	}
+
+	var b strings.Builder
+	for {
+		if p.pos >= len(p.s) {
+			return "", p.errorf("unterminated string")
+		}
+		if strings.HasPrefix(p.s[p.pos:], delim) {
+			p.pos += len(delim)
+			// Up to two quotes right before the closing delimiter belong to
+			// the content.
+			for i := 0; multiline && i < 2 && p.peek() == q; i++ {
+				b.WriteByte(q)
+				p.pos++
+			}
+			return b.String(), nil
+		}
+		c := p.s[p.pos]
+		if c == '\n' && !multiline {
+			return "", p.errorf("unterminated string")
+		}
+		if c != '\\' || q == '\'' {
+			b.WriteByte(c)
+			p.pos++
+			continue
+		}
+
+		p.pos++
+		if p.pos >= len(p.s) {
+			return "", p.errorf("unterminated string")
+		}
+		esc := p.s[p.pos]
+		p.pos++
+		switch esc {
+		case 'b':
+			b.WriteByte('\b')
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'f':
+			b.WriteByte('\f')
+		case 'r':
+			b.WriteByte('\r')
+		case 'e':
+			b.WriteByte(0x1b)
+		case '"', '\\':
+			b.WriteByte(esc)
+		case 'u', 'U':
+			n := 4
+			if esc == 'U' {
+				n = 8
+			}
+			if p.pos+n > len(p.s) {
+				return "", p.errorf("invalid unicode escape")
+			}
+			code, err := strconv.ParseUint(p.s[p.pos:p.pos+n], 16, 32)
+			if err != nil || !utf8.ValidRune(rune(code)) {
+				return "", p.errorf("invalid unicode escape")
+			}
+			b.WriteRune(rune(code))
+			p.pos += n
+		case ' ', '\t', '\r', '\n':
+			// A backslash at the end of a line in a multi-line string trims
+			// the line break and the whitespace that follows.
+			if !multiline {
+				return "", p.errorf("invalid escape \\%c", esc)
+			}
+			p.pos--
+			rest := strings.TrimLeft(p.s[p.pos:], " \t")
+			if !strings.HasPrefix(rest, "\n") && !strings.HasPrefix(rest, "\r\n") {
+				return "", p.errorf("invalid escape \\%c", esc)
+			}
+			p.pos = len(p.s) - len(strings.TrimLeft(rest, " \t\r\n"))
+		default:
+			return "", p.errorf("invalid escape \\%c", esc)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package dotenv
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_formatTOML(t *testing.T) {
+	const content = `# app config
+title = "demo"
+debug = true
+
+[database]
+host = "db.local"   # primary
+port = 5_432
+"max-conns" = 10
+tls.enabled = false
+replicas = [
+  "r1", # first
+  "r2",
+]
+
+[server.http]
+addr = ':8080'
+motd = """
+Hello \
+  world
+"""
+raw = '''
+C:\path'''
+limits = { rps = 100, burst = 20 }
+unicode = "caf\u00e9"
+`
+	env, err := ParseString(content, WithFormat(FormatTOML))
+	assertNoError(t, err)
+
+	want := map[string]string{
+		"title":                    "demo",
+		"debug":                    "true",
+		"DATABASE_HOST":            "db.local",
+		"DATABASE_PORT":            "5432",
+		"DATABASE_MAX_CONNS":       "10",
+		"DATABASE_TLS_ENABLED":     "false",
+		"DATABASE_REPLICAS_0":      "r1",
+		"DATABASE_REPLICAS_1":      "r2",
+		"SERVER_HTTP_ADDR":         ":8080",
+		"SERVER_HTTP_MOTD":         "Hello world\n",
+		"SERVER_HTTP_RAW":          `C:\path`,
+		"SERVER_HTTP_LIMITS_RPS":   "100",
+		"SERVER_HTTP_LIMITS_BURST": "20",
+		"SERVER_HTTP_UNICODE":      "café",
+	}
+	assertEqual(t, len(env), len(want))
+	for k, v := range want {
+		assertEqual(t, env[k], v)
+	}
+
+	for _, tc := range []struct{ name, content, reason string }{
+		{"array of tables", "[[items]]\n", "arrays of tables are not supported"},
+		{"missing equals", "a 1\n", "expected '=' after key"},
+		{"trailing garbage", "a = 1 2\n", `unexpected '2' after value`},
+		{"unterminated", "a = \"x\n", "unterminated string"},
+		{"missing value", "a =\n", "expected a value"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseString(tc.content, WithFormat(FormatTOML))
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected *ParseError, got %v", err)
+			}
+			assertEqual(t, perr.Reason, tc.reason)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package dotenv
+
+import "context"
+
+// Tracer starts spans around loading, so that it shows up in startup traces
+// without the package depending on a tracing library. Parsing is wrapped in
+// a dotenv.parse span and every file, key directory and provider read in a
+// dotenv.read span below it. An OpenTelemetry adapter is a few lines:
+//
+//	type otelTracer struct{ t trace.Tracer }
+//
+//	func (o otelTracer) Start(ctx context.Context, name string) (context.Context, dotenv.Span) {
+//		ctx, span := o.t.Start(ctx, name)
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) SetAttributes(kv ...any) { /* convert to attribute.KeyValue */ }
+//
+//	func (s otelSpan) End(err error) {
+//		if err != nil {
+//			s.RecordError(err)
+//			s.SetStatus(codes.Error, err.Error())
+//		}
+//		s.Span.End()
+//	}
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// SetAttributes records key-value pairs, like the arguments of Logger.
+	SetAttributes(kv ...any)
+	// End ends the span, with the error the operation failed with, if any.
+	End(err error)
+}
+
+// WithTracer traces loading with t. Providers receive the context of their
+// dotenv.read span, so their own spans nest below it.
+func WithTracer(t Tracer) Option {
+	return func(o *Options) {
+		o.Tracer = t
+	}
+}
+
+func (o Options) startSpan(ctx context.Context, name string) (context.Context, Span) {
+	if o.Tracer == nil {
+		return ctx, nopSpan{}
+	}
+	return o.Tracer.Start(ctx, name)
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttributes(...any) {}
+func (nopSpan) End(error)            {}
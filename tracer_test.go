@@ -0,0 +1,75 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+type spanKey struct{}
+
+// recordingTracer records finished spans as "parent/name attrs err".
+type recordingTracer struct {
+	spans []string
+}
+
+type recordingSpan struct {
+	t     *recordingTracer
+	path  string
+	attrs []string
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	path := name
+	if parent, ok := ctx.Value(spanKey{}).(string); ok {
+		path = parent + "/" + name
+	}
+	return context.WithValue(ctx, spanKey{}, path), &recordingSpan{t: t, path: path}
+}
+
+func (s *recordingSpan) SetAttributes(kv ...any) {
+	for i := 0; i+1 < len(kv); i += 2 {
+		s.attrs = append(s.attrs, fmt.Sprintf("%v=%v", kv[i], kv[i+1]))
+	}
+}
+
+func (s *recordingSpan) End(err error) {
+	line := s.path + " " + strings.Join(s.attrs, ",")
+	if err != nil {
+		line += " error"
+	}
+	s.t.spans = append(s.t.spans, line)
+}
+
+func Test_tracer(t *testing.T) {
+	fs := fstest.MapFS{".env": &fstest.MapFile{Data: []byte("A=1\nB=2\n")}}
+
+	tracer := &recordingTracer{}
+	var providerSpan string
+	provider := ProviderFunc(func(ctx context.Context) (map[string]string, error) {
+		providerSpan, _ = ctx.Value(spanKey{}).(string)
+		return map[string]string{"C": "3"}, nil
+	})
+	_, err := Parse(WithFs(fs), WithTracer(tracer), WithProviders(provider))
+	assertNoError(t, err)
+	assertEqual(t, strings.Join(tracer.spans, "\n"), strings.Join([]string{
+		"dotenv.parse/dotenv.read source=.env,keys=2",
+		"dotenv.parse/dotenv.read source=dotenv.ProviderFunc,keys=1",
+		"dotenv.parse keys=3",
+	}, "\n"))
+	assertEqual(t, providerSpan, "dotenv.parse/dotenv.read")
+
+	tracer = &recordingTracer{}
+	failing := ProviderFunc(func(context.Context) (map[string]string, error) {
+		return nil, errors.New("boom")
+	})
+	_, err = Parse(WithFs(fs), WithTracer(tracer), WithProviders(failing))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	assertEqual(t, tracer.spans[1], "dotenv.parse/dotenv.read source=dotenv.ProviderFunc,keys=0 error")
+	assertEqual(t, tracer.spans[2], "dotenv.parse keys=0 error")
+}
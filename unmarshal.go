@@ -0,0 +1,159 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+)
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// Unmarshal parses the configured paths like Parse and stores the values in
+// the struct pointed to by v. Fields are matched by their `env:"KEY"` tag;
+// untagged fields and fields tagged `env:"-"` are left alone. Nested structs
+// are walked recursively and, when tagged, their tag is used as a prefix
+// joined with '_' (`env:"DB"` + `env:"HOST"` reads DB_HOST).
+//
+// Keys missing from the files fall back to the process environment, so the
+// result matches what Load followed by os.Getenv would produce. Fields whose
+// key is found nowhere are set from their `default` tag if they have one and
+// keep their current value otherwise. GenerateExample and GenerateMarkdown
+// document such a struct, using its `desc` tags.
+//
+// Supported field kinds are string, bool, signed and unsigned integers,
+// floats, time.Duration and nested structs.
+func Unmarshal(v any, userOptions ...Option) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("unmarshal target should be a non-nil pointer to a struct")
+	}
+
+	opts, err := buildOptions(userOptions)
+	if err != nil {
+		return fmt.Errorf("can't unmarshal .env file with these options: %w", err)
+	}
+
+	env, err := parse(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+
+	lookup := func(key string) (string, bool) {
+		if opts.NoOverride {
+			if val, ok := os.LookupEnv(key); ok {
+				return val, true
+			}
+		}
+		if e, ok := env.lookup(key); ok {
+			return e.value, true
+		}
+		return os.LookupEnv(key)
+	}
+
+	rv = rv.Elem()
+	for _, f := range envFields(rv.Type()) {
+		raw, ok := lookup(f.key)
+		if !ok {
+			raw, ok = f.field.Tag.Lookup("default")
+		}
+		if !ok {
+			continue
+		}
+		if err := setField(rv.FieldByIndex(f.index), raw); err != nil {
+			return fmt.Errorf("set field %s from %s: %w", f.field.Name, f.key, err)
+		}
+	}
+	return nil
+}
+
+// envField is a struct field bound to a variable by its `env` tag.
+type envField struct {
+	field reflect.StructField
+	// key is the tag joined to the prefixes of the enclosing structs.
+	key string
+	// index locates the field for reflect.Value.FieldByIndex.
+	index []int
+}
+
+// envFields lists the fields of the struct type rt bound to variables, in
+// field order. Nested structs are walked recursively, prefixing the keys of
+// their fields with their tag and '_' when they have one. `env:"-"` skips a
+// field, nested structs included.
+func envFields(rt reflect.Type) []envField {
+	var fields []envField
+	var walk func(rt reflect.Type, prefix string, index []int)
+	walk = func(rt reflect.Type, prefix string, index []int) {
+		for i := 0; i < rt.NumField(); i++ {
+			field := rt.Field(i)
+			tag, tagged := field.Tag.Lookup("env")
+			if !field.IsExported() || tag == "-" {
+				continue
+			}
+
+			fieldIndex := append(slices.Clip(index), i)
+			if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+				nestedPrefix := prefix
+				if tagged && tag != "" {
+					nestedPrefix = prefix + tag + "_"
+				}
+				walk(field.Type, nestedPrefix, fieldIndex)
+				continue
+			}
+
+			if !tagged || tag == "" {
+				continue
+			}
+			fields = append(fields, envField{field: field, key: prefix + tag, index: fieldIndex})
+		}
+	}
+	walk(rt, "", nil)
+	return fields
+}
+
+func setField(fv reflect.Value, raw string) error {
+	if fv.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+	return nil
+}
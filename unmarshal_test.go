@@ -0,0 +1,108 @@
+package dotenv
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func Test_unmarshal(t *testing.T) {
+	type database struct {
+		Host string `env:"HOST"`
+		Port uint16 `env:"PORT"`
+	}
+	type config struct {
+		Name    string        `env:"NAME"`
+		Debug   bool          `env:"DEBUG"`
+		Workers int           `env:"WORKERS"`
+		Ratio   float64       `env:"RATIO"`
+		Timeout time.Duration `env:"TIMEOUT"`
+		DB      database      `env:"DB"`
+		Keep    string        `env:"MISSING_DOTENV_KEY"`
+		ignored string
+	}
+
+	t.Run("fills tagged fields", func(t *testing.T) {
+		fs := fstest.MapFS{
+			".env": &fstest.MapFile{Data: []byte(`NAME=api
+DEBUG=true
+WORKERS=4
+RATIO=0.5
+TIMEOUT=1m30s
+DB_HOST=db.local
+DB_PORT=5432
+`)},
+		}
+
+		cfg := config{Keep: "default"}
+		err := Unmarshal(&cfg, WithFs(fs))
+		assertNoError(t, err)
+		assertEqual(t, cfg.Name, "api")
+		assertEqual(t, cfg.Debug, true)
+		assertEqual(t, cfg.Workers, 4)
+		assertEqual(t, cfg.Ratio, 0.5)
+		assertEqual(t, cfg.Timeout, 90*time.Second)
+		assertEqual(t, cfg.DB.Host, "db.local")
+		assertEqual(t, cfg.DB.Port, uint16(5432))
+		assertEqual(t, cfg.Keep, "default")
+	})
+
+	t.Run("falls back to process environment", func(t *testing.T) {
+		t.Setenv("NAME", "from-env")
+		cfg := config{}
+		err := Unmarshal(&cfg, WithFs(fstest.MapFS{}))
+		assertNoError(t, err)
+		assertEqual(t, cfg.Name, "from-env")
+	})
+
+	t.Run("uses default tags for missing keys", func(t *testing.T) {
+		type withDefaults struct {
+			Port  int    `env:"DOTENV_TEST_PORT" default:"8080"`
+			Level string `env:"DOTENV_TEST_LEVEL" default:"info"`
+		}
+		fs := fstest.MapFS{
+			".env": &fstest.MapFile{Data: []byte("DOTENV_TEST_LEVEL=debug\n")},
+		}
+		cfg := withDefaults{}
+		err := Unmarshal(&cfg, WithFs(fs))
+		assertNoError(t, err)
+		assertEqual(t, cfg.Port, 8080)
+		assertEqual(t, cfg.Level, "debug")
+	})
+
+	t.Run("skips nested structs tagged -", func(t *testing.T) {
+		type skipped struct {
+			Inner struct {
+				Field string `env:"FIELD"`
+			} `env:"-"`
+			Name string `env:"NAME"`
+		}
+		fs := fstest.MapFS{
+			".env": &fstest.MapFile{Data: []byte("NAME=api\nFIELD=y\n")},
+		}
+		t.Setenv("-_FIELD", "x")
+		cfg := skipped{}
+		err := Unmarshal(&cfg, WithFs(fs))
+		assertNoError(t, err)
+		assertEqual(t, cfg.Name, "api")
+		assertEqual(t, cfg.Inner.Field, "")
+	})
+
+	t.Run("reports conversion errors", func(t *testing.T) {
+		fs := fstest.MapFS{
+			".env": &fstest.MapFile{Data: []byte("WORKERS=many\n")},
+		}
+		err := Unmarshal(&config{}, WithFs(fs))
+		if err == nil || !strings.Contains(err.Error(), "WORKERS") {
+			t.Fatalf("expected error mentioning WORKERS, got %v", err)
+		}
+	})
+
+	t.Run("rejects non-pointer targets", func(t *testing.T) {
+		err := Unmarshal(config{})
+		if err == nil {
+			t.Fatal("expected error for non-pointer target")
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package dotenv
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"strings"
+)
+
+// WithVault enables .env.vault files in the dotenv-vault format. When the
+// DOTENV_KEY environment variable is set, or the KeyProvider or WithVaultKey
+// supply the key, every file that has a .vault sibling, such as .env next
+// to .env.vault, is read from the vault instead; files without one are read
+// as usual.
+//
+// A vault holds one encrypted copy of the file per environment, in keys
+// like DOTENV_VAULT_PRODUCTION. DOTENV_KEY names the environment and
+// carries the decryption key:
+//
+//	DOTENV_KEY='dotenv://:key_1234…@dotenv.org/vault/.env.vault?environment=production'
+//
+// Several comma separated keys may be given, for rotation; the first that
+// decrypts its environment wins.
+func WithVault() Option {
+	return func(o *Options) {
+		o.Vault = true
+	}
+}
+
+// WithVaultKey enables vaults like WithVault, using key instead of
+// DOTENV_KEY.
+func WithVaultKey(key string) Option {
+	return func(o *Options) {
+		o.Vault = true
+		o.VaultKey = key
+	}
+}
+
+func (o Options) vaultKey(ctx context.Context) (string, error) {
+	if o.VaultKey != "" {
+		return o.VaultKey, nil
+	}
+	return o.key(ctx, "DOTENV_KEY")
+}
+
+// vaultPaths replaces every path that has a .vault sibling with the vault
+// when a key is available.
+func vaultPaths(ctx context.Context, opts Options, envPaths []string) ([]string, error) {
+	if !opts.Vault {
+		return envPaths, nil
+	}
+	if key, err := opts.vaultKey(ctx); err != nil || key == "" {
+		return envPaths, err
+	}
+	paths := make([]string, len(envPaths))
+	for i, envPath := range envPaths {
+		paths[i] = envPath
+		if strings.HasSuffix(envPath, ".vault") {
+			continue
+		}
+		_, err := fs.Stat(opts.RootFs, envPath+".vault")
+		switch {
+		case err == nil:
+			paths[i] = envPath + ".vault"
+		case errors.Is(err, fs.ErrNotExist):
+			opts.Logger.Warn("vault not found; reading plain file", "path", envPath)
+		default:
+			return nil, fmt.Errorf("stat %s: %w", envPath+".vault", err)
+		}
+	}
+	return paths, nil
+}
+
+// openVault decrypts the environment selected by the vault key from the
+// vault read from r.
+func openVault(ctx context.Context, opts Options, r io.Reader, envPath string) (io.Reader, error) {
+	key, err := opts.vaultKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt %s: %w", envPath, err)
+	}
+	if key == "" {
+		return nil, fmt.Errorf("decrypt %s: DOTENV_KEY is not set", envPath)
+	}
+	vault, err := parseReader(ctx, r, envPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for k := range strings.SplitSeq(key, ",") {
+		plain, err := decryptVault(vault, strings.TrimSpace(k))
+		if err == nil {
+			return bytes.NewReader(plain), nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, fmt.Errorf("decrypt %s: %w", envPath, errors.Join(errs...))
+}
+
+// decryptVault decrypts the environment named by a single DOTENV_KEY.
+func decryptVault(vault map[string]string, dotenvKey string) ([]byte, error) {
+	u, err := url.Parse(dotenvKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DOTENV_KEY: %w", err)
+	}
+	password, _ := u.User.Password()
+	if password == "" {
+		return nil, errors.New("invalid DOTENV_KEY: missing key part")
+	}
+	environment := u.Query().Get("environment")
+	if environment == "" {
+		return nil, errors.New("invalid DOTENV_KEY: missing environment part")
+	}
+
+	name := "DOTENV_VAULT_" + strings.ToUpper(environment)
+	ciphertext, ok := vault[name]
+	if !ok {
+		return nil, fmt.Errorf("cannot locate environment %s in the vault", name)
+	}
+	if len(password) < 64 {
+		return nil, errors.New("invalid DOTENV_KEY: key part must be 64 hex characters")
+	}
+	key, err := hex.DecodeString(password[len(password)-64:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid DOTENV_KEY: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, fmt.Errorf("%s: ciphertext too short", name)
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: decryption failed, check DOTENV_KEY", name)
+	}
+	return plain, nil
+}
@@ -0,0 +1,86 @@
+package dotenv
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// sealVault encrypts plain like dotenv-vault does and returns the DOTENV_KEY
+// and the vault value.
+func sealVault(t *testing.T, environment, plain string) (string, string) {
+	t.Helper()
+	key := make([]byte, 32)
+	rand.Read(key)
+	block, err := aes.NewCipher(key)
+	assertNoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	assertNoError(t, err)
+	nonce := make([]byte, gcm.NonceSize())
+	rand.Read(nonce)
+	sealed := gcm.Seal(nonce, nonce, []byte(plain), nil)
+
+	dotenvKey := "dotenv://:key_" + hex.EncodeToString(key) + "@dotenv.org/vault/.env.vault?environment=" + environment
+	return dotenvKey, base64.StdEncoding.EncodeToString(sealed)
+}
+
+func Test_vault(t *testing.T) {
+	prodKey, prod := sealVault(t, "production", "SECRET=prod\nSHARED=${SECRET}-x\n")
+	devKey, dev := sealVault(t, "development", "SECRET=dev\n")
+	fs := fstest.MapFS{
+		".env":       &fstest.MapFile{Data: []byte("SECRET=plain\n")},
+		".env.vault": &fstest.MapFile{Data: []byte("DOTENV_VAULT_PRODUCTION=\"" + prod + "\"\nDOTENV_VAULT_DEVELOPMENT=\"" + dev + "\"\n")},
+	}
+
+	t.Run("reads the environment named by the key", func(t *testing.T) {
+		t.Setenv("DOTENV_KEY", prodKey)
+		env, err := Parse(WithFs(fs), WithVault(), WithExpand(true))
+		assertNoError(t, err)
+		assertEqual(t, env["SECRET"], "prod")
+		assertEqual(t, env["SHARED"], "prod-x")
+
+		env, err = Parse(WithFs(fs), WithVaultKey(devKey))
+		assertNoError(t, err)
+		assertEqual(t, env["SECRET"], "dev")
+	})
+
+	t.Run("plain file without key", func(t *testing.T) {
+		t.Setenv("DOTENV_KEY", "")
+		env, err := Parse(WithFs(fs), WithVault())
+		assertNoError(t, err)
+		assertEqual(t, env["SECRET"], "plain")
+
+		_, err = Parse(WithFs(fs), WithVault(), WithPaths(".env.vault"))
+		if err == nil || !strings.Contains(err.Error(), "DOTENV_KEY is not set") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("key rotation", func(t *testing.T) {
+		otherKey, _ := sealVault(t, "production", "")
+		env, err := Parse(WithFs(fs), WithVaultKey(otherKey+","+prodKey))
+		assertNoError(t, err)
+		assertEqual(t, env["SECRET"], "prod")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		otherKey, _ := sealVault(t, "production", "")
+		stagingKey, _ := sealVault(t, "staging", "")
+		for key, want := range map[string]string{
+			otherKey:                "decryption failed",
+			stagingKey:              "cannot locate environment DOTENV_VAULT_STAGING",
+			"dotenv://dotenv.org/x": "missing key part",
+			"dotenv://:key_00@dotenv.org/vault/.env.vault?environment=production": "64 hex characters",
+		} {
+			_, err := Parse(WithFs(fs), WithVaultKey(key))
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: want %q, got %v", key, want, err)
+			}
+		}
+	})
+}
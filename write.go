@@ -0,0 +1,72 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WriteFile marshals env and atomically writes it to filename, see Marshal
+// for the format.
+func WriteFile(filename string, env map[string]string) error {
+	data, err := Marshal(env)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filename, data, 0o600)
+}
+
+// Save atomically writes the document to filename.
+func (d *Document) Save(filename string) error {
+	return writeFileAtomic(filename, d.Bytes(), 0o600)
+}
+
+// writeFileAtomic writes data to a temporary file next to filename, syncs it
+// and renames it over filename, so readers never observe a partially written
+// file. An existing file keeps its permissions; new files get perm, 0600 for
+// dotenv files since they usually hold secrets.
+func writeFileAtomic(filename string, data []byte, perm fs.FileMode) (err error) {
+	if info, err := os.Stat(filename); err == nil {
+		perm = info.Mode().Perm()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("stat %s: %w", filename, err)
+	}
+
+	dir := filepath.Dir(filename)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("create temp file for %s: %w", filename, err)
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", tmp.Name(), err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		return fmt.Errorf("chmod %s: %w", tmp.Name(), err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("sync %s: %w", tmp.Name(), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", tmp.Name(), err)
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("rename %s to %s: %w", tmp.Name(), filename, err)
+	}
+
+	// Persist the rename itself. Directories can't be opened for syncing on
+	// every platform, so this is best effort.
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_writeFile(t *testing.T) {
+	t.Run("writes new file with private permissions", func(t *testing.T) {
+		dir := t.TempDir()
+		name := filepath.Join(dir, ".env")
+
+		err := WriteFile(name, map[string]string{"A": "1", "B": "two words"})
+		assertNoError(t, err)
+
+		data, err := os.ReadFile(name)
+		assertNoError(t, err)
+		assertEqual(t, string(data), "A=1\nB='two words'\n")
+
+		info, err := os.Stat(name)
+		assertNoError(t, err)
+		assertEqual(t, info.Mode().Perm(), os.FileMode(0o600))
+
+		entries, err := os.ReadDir(dir)
+		assertNoError(t, err)
+		assertEqual(t, len(entries), 1)
+	})
+
+	t.Run("document save keeps existing permissions", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), ".env")
+		assertNoError(t, os.WriteFile(name, []byte("# keep\nA=1\n"), 0o640))
+		assertNoError(t, os.Chmod(name, 0o640))
+
+		f, err := os.Open(name)
+		assertNoError(t, err)
+		doc, err := ParseDocument(f)
+		f.Close()
+		assertNoError(t, err)
+		assertNoError(t, doc.Set("A", "2"))
+		assertNoError(t, doc.Save(name))
+
+		data, err := os.ReadFile(name)
+		assertNoError(t, err)
+		assertEqual(t, string(data), "# keep\nA=2\n")
+
+		info, err := os.Stat(name)
+		assertNoError(t, err)
+		assertEqual(t, info.Mode().Perm(), os.FileMode(0o640))
+	})
+
+	t.Run("leaves original untouched on marshal error", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), ".env")
+		assertNoError(t, os.WriteFile(name, []byte("A=1\n"), 0o600))
+
+		err := WriteFile(name, map[string]string{"BAD KEY": "1"})
+		if err == nil || !strings.Contains(err.Error(), "BAD KEY") {
+			t.Fatalf("expected marshal error, got %v", err)
+		}
+		data, err := os.ReadFile(name)
+		assertNoError(t, err)
+		assertEqual(t, string(data), "A=1\n")
+	})
+}
@@ -0,0 +1,329 @@
+package dotenv
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// scanYAML reads a YAML mapping and calls fn with a statement per leaf, see
+// FormatYAML.
+func scanYAML(_ Options, r io.Reader, envPath string, fn func(statement) error) error {
+	var lines []string
+	scanner := newLineReader(r, envPath, 0)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read %s: %w", envPath, err)
+	}
+	p := &yamlParser{file: envPath, lines: lines, emit: fn}
+	return p.parse()
+}
+
+type yamlParser struct {
+	file  string
+	lines []string
+	emit  func(statement) error
+	stack []yamlFrame
+}
+
+// yamlFrame is a mapping or sequence whose items are being read.
+type yamlFrame struct {
+	prefix string
+	// line is where the frame's key was defined.
+	line int
+	// indent of the items, -1 until the first item was seen.
+	indent int
+	// parentIndent is the indentation of the key owning the frame.
+	parentIndent int
+	seq          bool
+	items        int
+}
+
+func (p *yamlParser) errorAt(line, col int, format string, args ...any) error {
+	return &ParseError{File: p.file, Line: line, Col: col, Reason: fmt.Sprintf(format, args...)}
+}
+
+func (p *yamlParser) set(line int, key, value string) error {
+	return p.emit(statement{line: line, key: key, value: value, quote: '\''})
+}
+
+func (p *yamlParser) parse() error {
+	p.stack = []yamlFrame{{indent: -1, parentIndent: -1}}
+	for i := 0; i < len(p.lines); i++ {
+		raw := p.lines[i]
+		lineNo := i + 1
+		content := strings.TrimLeft(raw, " ")
+		if strings.TrimSpace(content) == "" || content[0] == '#' {
+			continue
+		}
+		if strings.HasPrefix(raw, "\t") {
+			return p.errorAt(lineNo, 1, "tabs are not allowed for indentation")
+		}
+		if (content == "---" || strings.HasPrefix(content, "--- ")) && len(p.stack) == 1 && p.stack[0].items == 0 {
+			continue
+		}
+		if content == "..." {
+			break
+		}
+		indent := len(raw) - len(content)
+		item := content == "-" || strings.HasPrefix(content, "- ")
+
+		if err := p.enter(indent, item, lineNo); err != nil {
+			return err
+		}
+		top := &p.stack[len(p.stack)-1]
+		top.items++
+
+		if item {
+			if !top.seq {
+				return p.errorAt(lineNo, indent+1, "unexpected sequence item")
+			}
+			value := strings.TrimSpace(strings.TrimPrefix(content, "-"))
+			key := joinYAMLKey(top.prefix, strconv.Itoa(top.items-1))
+			if _, _, ok := cutYAMLKey(value); ok || value == "" {
+				return p.errorAt(lineNo, indent+1, "only sequences of scalars are supported")
+			}
+			next, err := p.scalar(key, value, lineNo, indent, &i)
+			if err != nil {
+				return err
+			}
+			if err := p.set(lineNo, key, next); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if top.seq {
+			return p.errorAt(lineNo, indent+1, "expected a sequence item")
+		}
+		name, rest, ok := cutYAMLKey(content)
+		if !ok {
+			return p.errorAt(lineNo, indent+1, "expected key: value")
+		}
+		key := joinYAMLKey(top.prefix, name)
+		rest = strings.TrimSpace(rest)
+		if rest == "" || rest[0] == '#' {
+			p.stack = append(p.stack, yamlFrame{prefix: key, line: lineNo, indent: -1, parentIndent: indent})
+			continue
+		}
+		value, err := p.scalar(key, rest, lineNo, indent, &i)
+		if err != nil {
+			return err
+		}
+		if err := p.set(lineNo, key, value); err != nil {
+			return err
+		}
+	}
+	for len(p.stack) > 1 {
+		if err := p.pop(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enter pops the frames the line at indent does not belong to.
+func (p *yamlParser) enter(indent int, item bool, lineNo int) error {
+	for {
+		top := &p.stack[len(p.stack)-1]
+		if top.indent < 0 {
+			if indent > top.parentIndent || (item && indent == top.parentIndent && len(p.stack) > 1) {
+				top.indent = indent
+				top.seq = item
+				return nil
+			}
+			if err := p.pop(); err != nil {
+				return err
+			}
+			continue
+		}
+		if indent == top.indent && (item || !top.seq || top.indent > top.parentIndent) {
+			return nil
+		}
+		if indent == top.indent {
+			// A key next to the items of a sequence written at the
+			// indentation of its own key belongs to the enclosing mapping.
+			if err := p.pop(); err != nil {
+				return err
+			}
+			continue
+		}
+		if indent > top.indent || len(p.stack) == 1 {
+			return p.errorAt(lineNo, indent+1, "unexpected indentation")
+		}
+		if err := p.pop(); err != nil {
+			return err
+		}
+	}
+}
+
+// pop closes the innermost frame. A key without any items is null.
+func (p *yamlParser) pop() error {
+	top := p.stack[len(p.stack)-1]
+	p.stack = p.stack[:len(p.stack)-1]
+	if top.items == 0 && top.prefix != "" {
+		return p.set(top.line, top.prefix, "")
+	}
+	return nil
+}
+
+// scalar parses the value of key starting on line lineNo. Block scalars
+// consume the following lines, advancing *i.
+func (p *yamlParser) scalar(key, s string, lineNo, indent int, i *int) (string, error) {
+	col := indent + 1
+	switch s[0] {
+	case '|', '>':
+		header, _, _ := strings.Cut(s, " #")
+		header = strings.TrimSpace(header)
+		if header != s[:1] && header != s[:1]+"-" && header != s[:1]+"+" {
+			return "", p.errorAt(lineNo, col, "unsupported block scalar header %q", header)
+		}
+		return p.blockScalar(header, indent, i), nil
+	case '"':
+		end := closingQuote(s[1:], '"', true) + 1
+		if end == 0 || !isYAMLComment(s[end+1:]) {
+			return "", p.errorAt(lineNo, col, "unterminated quoted value for %s", key)
+		}
+		v, err := strconv.Unquote(s[:end+1])
+		if err != nil {
+			return "", p.errorAt(lineNo, col, "invalid quoted value for %s: %v", key, err)
+		}
+		return v, nil
+	case '\'':
+		var b strings.Builder
+		for j := 1; j < len(s); j++ {
+			if s[j] != '\'' {
+				b.WriteByte(s[j])
+				continue
+			}
+			if j+1 < len(s) && s[j+1] == '\'' {
+				b.WriteByte('\'')
+				j++
+				continue
+			}
+			if !isYAMLComment(s[j+1:]) {
+				break
+			}
+			return b.String(), nil
+		}
+		return "", p.errorAt(lineNo, col, "unterminated quoted value for %s", key)
+	case '[', '{':
+		v := strings.TrimSpace(stripYAMLComment(s))
+		if v == "[]" || v == "{}" {
+			return "", nil
+		}
+		return "", p.errorAt(lineNo, col, "flow collections are not supported")
+	case '&', '*', '!':
+		return "", p.errorAt(lineNo, col, "anchors, aliases and tags are not supported")
+	}
+
+	v := strings.TrimSpace(stripYAMLComment(s))
+	switch v {
+	case "~", "null", "Null", "NULL":
+		return "", nil
+	}
+	return v, nil
+}
+
+// blockScalar reads the lines of a | or > block following line *i.
+func (p *yamlParser) blockScalar(header string, indent int, i *int) string {
+	var body []string
+	blockIndent := -1
+	for *i+1 < len(p.lines) {
+		next := p.lines[*i+1]
+		trimmed := strings.TrimLeft(next, " ")
+		if trimmed == "" {
+			body = append(body, "")
+			*i++
+			continue
+		}
+		n := len(next) - len(trimmed)
+		if blockIndent < 0 {
+			blockIndent = n
+		}
+		if n <= indent || n < blockIndent {
+			break
+		}
+		body = append(body, next[blockIndent:])
+		*i++
+	}
+	// Trailing blank lines belong to chomping, not to the content.
+	trailing := 0
+	for len(body) > 0 && body[len(body)-1] == "" {
+		body = body[:len(body)-1]
+		trailing++
+	}
+
+	var v string
+	if header[0] == '|' {
+		v = strings.Join(body, "\n")
+	} else {
+		var b strings.Builder
+		for j, l := range body {
+			// A blank line folds into a newline; other line breaks become
+			// spaces.
+			switch {
+			case l == "":
+				b.WriteByte('\n')
+			case j > 0 && body[j-1] != "":
+				b.WriteByte(' ')
+			}
+			b.WriteString(l)
+		}
+		v = b.String()
+	}
+	switch {
+	case strings.HasSuffix(header, "-") || v == "":
+	case strings.HasSuffix(header, "+"):
+		v += strings.Repeat("\n", trailing+1)
+	default:
+		v += "\n"
+	}
+	return v
+}
+
+// cutYAMLKey splits "key: value" and unquotes the key.
+func cutYAMLKey(s string) (key, rest string, ok bool) {
+	if isQuote(s) {
+		end := closingQuote(s[1:], s[0], s[0] == '"') + 1
+		if end > 0 && strings.HasPrefix(s[end+1:], ":") {
+			key = s[1:end]
+			if s[0] == '"' {
+				if k, err := strconv.Unquote(s[:end+1]); err == nil {
+					key = k
+				}
+			}
+			rest = s[end+2:]
+			return key, rest, rest == "" || rest[0] == ' '
+		}
+	}
+	if i := strings.Index(s, ": "); i > 0 {
+		return strings.TrimSpace(s[:i]), s[i+2:], true
+	}
+	if k, ok := strings.CutSuffix(s, ":"); ok && k != "" {
+		return strings.TrimSpace(k), "", true
+	}
+	return "", "", false
+}
+
+func joinYAMLKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// stripYAMLComment removes a " #" comment from a plain scalar.
+func stripYAMLComment(s string) string {
+	if i := inlineComment(s); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+func isYAMLComment(rest string) bool {
+	return isInlineComment(rest)
+}
@@ -0,0 +1,92 @@
+package dotenv
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_formatYAML(t *testing.T) {
+	const content = `---
+# service config
+name: api
+port: 8080 # default
+empty:
+quoted: "a\tb # not a comment"
+single: 'it''s'
+url: http://example.com:8080/x
+db:
+  host: db.local
+  "pool size": 10
+  replicas:
+  - r1
+  - r2
+  tls:
+    enabled: true
+cert: |
+  -----BEGIN-----
+  abc
+  -----END-----
+folded: >-
+  one
+  two
+
+  three
+nothing: ~
+list: []
+`
+	env, err := ParseString(content, WithFormat(FormatYAML))
+	assertNoError(t, err)
+
+	want := map[string]string{
+		"name":           "api",
+		"port":           "8080",
+		"empty":          "",
+		"quoted":         "a\tb # not a comment",
+		"single":         "it's",
+		"url":            "http://example.com:8080/x",
+		"db.host":        "db.local",
+		"db.pool size":   "10",
+		"db.replicas.0":  "r1",
+		"db.replicas.1":  "r2",
+		"db.tls.enabled": "true",
+		"cert":           "-----BEGIN-----\nabc\n-----END-----\n",
+		"folded":         "one two\nthree",
+		"nothing":        "",
+		"list":           "",
+	}
+	assertEqual(t, len(env), len(want))
+	for k, v := range want {
+		assertEqual(t, env[k], v)
+	}
+
+	t.Run("normalized through the pipeline", func(t *testing.T) {
+		fs := fstest.MapFS{
+			".env":      &fstest.MapFile{Data: []byte("DB_HOST=override\n")},
+			".env.yaml": &fstest.MapFile{Data: []byte("db:\n  host: x\n  port: 1\n")},
+		}
+		env, err := Parse(WithPaths(".env.yaml", ".env"), WithFs(fs), WithFormat(FormatAuto),
+			WithKeyNormalizer(NormalizeUpperSnake))
+		assertNoError(t, err)
+		assertEqual(t, env["DB_HOST"], "override")
+		assertEqual(t, env["DB_PORT"], "1")
+	})
+
+	for _, tc := range []struct{ name, content, reason string }{
+		{"flow", "a: [1, 2]\n", "flow collections are not supported"},
+		{"anchor", "a: &x 1\n", "anchors, aliases and tags are not supported"},
+		{"indent", "a: 1\n  b: 2\n", "unexpected indentation"},
+		{"not a mapping", "just text\n", "expected key: value"},
+		{"nested sequence", "a:\n  - b: 1\n", "only sequences of scalars are supported"},
+		{"unterminated", "a: \"x\n", "unterminated quoted value for a"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseString(tc.content, WithFormat(FormatYAML))
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected *ParseError, got %v", err)
+			}
+			assertEqual(t, perr.Reason, tc.reason)
+		})
+	}
+}